@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+// add adds a bookmark into the bookmark file of chrome or chromium-based browsers
+func add(cfg *Config, args ...string) {
+	if err := runAdd(cfg, os.Stdout, args...); err != nil {
+		awf.Fatal("failed to add the bookmark", err.Error())
+	}
+}
+
+func runAdd(cfg *Config, out io.Writer, args ...string) error {
+	var browser, folder, title, profile string
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVarP(&browser, "browser", "b", string(bookmarker.Chrome), "chrome or a chromium-based browser like brave")
+	fs.StringVarP(&folder, "folder", "f", "", "folder path like /Bookmarks Bar/work. other bookmarks if empty")
+	fs.StringVarP(&title, "title", "t", "", "title of the bookmark. the url if empty")
+	fs.StringVarP(&profile, "profile", "p", "", "profile of the browser. the configured profile if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("a url is required")
+	}
+
+	s, ok := bookmarker.LookupSource(browser)
+	if !ok || s.Add == nil {
+		return fmt.Errorf("adding bookmarks is not supported: %s", browser)
+	}
+	c, ok := cfg.Sources[browser].(*bookmarker.ProfileConfig)
+	if !ok {
+		return fmt.Errorf("%s is not configured", browser)
+	}
+	if profile == "" {
+		profiles := c.Profiles()
+		if len(profiles) != 1 {
+			return errors.New("--profile is required as multiple profiles are configured")
+		}
+		profile = profiles[0]
+	}
+
+	b, err := s.Add(c, profile, folder, title, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "added %s to %s\n", b.URI, b.Folder)
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+func Test_runAdd(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "pkg", "bookmarker", "testdata", "test-chrome-bookmarks-checksum.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profilePath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(profilePath, "Default"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	bookmarkPath := filepath.Join(profilePath, "Default", "Bookmarks")
+	if err := os.WriteFile(bookmarkPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	chrome := testProfileConfig("chrome")
+	chrome.ProfilePath = profilePath
+	chrome.ProfileName = "Default"
+	bravePath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(bravePath, "Default"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bravePath, "Default", "Bookmarks"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	brave := testProfileConfig("brave")
+	brave.ProfilePath = bravePath
+	brave.ProfileName = "Default"
+	cfg := &Config{
+		Sources: map[string]bookmarker.SourceConfig{
+			"chrome":  chrome,
+			"brave":   brave,
+			"firefox": testProfileConfig("firefox"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		want      string
+		expectErr error
+	}{
+		{
+			name: "add into the folder",
+			args: []string{"-f", "/Bookmarks bar/Go 言語", "--title", "Go", "https://go.dev/doc/"},
+			want: "added https://go.dev/doc/ to /Bookmarks bar/Go 言語\n",
+		},
+		{
+			name: "add into other bookmarks",
+			args: []string{"https://pkg.go.dev/"},
+			want: "added https://pkg.go.dev/ to /Other bookmarks\n",
+		},
+		{
+			name: "add into a chromium-based browser",
+			args: []string{"-b", "brave", "https://brave.com/"},
+			want: "added https://brave.com/ to /Other bookmarks\n",
+		},
+		{
+			name:      "browser which does not support adding",
+			args:      []string{"-b", "firefox", "https://go.dev/"},
+			expectErr: errors.New("adding bookmarks is not supported: firefox"),
+		},
+		{
+			name:      "browser which is not configured",
+			args:      []string{"-b", "edge", "https://go.dev/"},
+			expectErr: errors.New("edge is not configured"),
+		},
+		{
+			name:      "unknown folder",
+			args:      []string{"-f", "/Bookmarks bar/unknown", "https://go.dev/"},
+			expectErr: bookmarker.ErrFolderNotFound,
+		},
+		{
+			name:      "no url",
+			args:      []string{"-f", "/Bookmarks bar"},
+			expectErr: errors.New("a url is required"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := runAdd(cfg, buf, tt.args...)
+			if tt.expectErr != nil {
+				if err == nil {
+					t.Fatalf("expect error happens, but got response")
+				}
+				if !errors.Is(err, tt.expectErr) && err.Error() != tt.expectErr.Error() {
+					t.Errorf("want: %v\ngot: %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("want: %q\ngot: %q", tt.want, got)
+			}
+		})
+	}
+
+	bookmarks, err := bookmarker.NewChrome(bookmarkPath).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, b := range bookmarks {
+		if b.URI == "https://go.dev/doc/" || b.URI == "https://pkg.go.dev/" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("want 2 added bookmarks, got %d", found)
+	}
+
+	bookmarks, err = bookmarker.NewBrave(filepath.Join(bravePath, "Default", "Bookmarks")).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := bookmarks[len(bookmarks)-1]; b.URI != "https://brave.com/" || b.BookmarkerName != bookmarker.Brave {
+		t.Errorf("the bookmark should be added to brave %+v", b)
+	}
+}
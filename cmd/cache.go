@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+// cacheVersion is a version of the cache format.
+// Bump it when Bookmark or bookmarkCache changes, then old caches are ignored
+const cacheVersion = 12
+
+// volatileCacheAge is an age of the cache while changes of volatile files are ignored.
+// Browsers write places.sqlite and history databases on every visit and reading them copies the whole databases,
+// so new bookmarks of firefox are searchable after the age at the latest
+const volatileCacheAge = 5 * time.Minute
+
+var (
+	errCacheExpired = errors.New("cache expired")
+	errCacheChanged = errors.New("bookmark files are changed")
+	errCacheVersion = errors.New("cache version is changed")
+	errCacheConfig  = errors.New("configuration is changed")
+)
+
+// bookmarkCache is bookmarks with fingerprints of bookmark files and the hash of the configuration
+// which the bookmarks are made from
+type bookmarkCache struct {
+	CreatedAt    time.Time
+	ConfigHash   string
+	Fingerprints bookmarker.Fingerprints
+	Bookmarks    bookmarker.Bookmarks
+}
+
+// cacheConfigHash returns a hash of the configuration which makes bookmarks of the cache.
+// Note: options which apply only to rendering like search weights are excluded not to rebuild the cache
+func cacheConfigHash(cfg *Config) (string, error) {
+	data, err := json.Marshal(struct {
+		Sources           map[string]bookmarker.SourceConfig
+		RemoveDuplicates  bool
+		Visits            bool
+		Favicons          bool
+		HistoryMaxAgeDays int
+		HistoryMaxEntries int
+		BrowserPriority   []string
+		URLNormalization  *bookmarker.URLNormalizer
+	}{
+		Sources:           cfg.Sources,
+		RemoveDuplicates:  cfg.RemoveDuplicates,
+		Visits:            cfg.Visits,
+		Favicons:          cfg.Favicons,
+		HistoryMaxAgeDays: cfg.HistoryMaxAgeDays,
+		HistoryMaxEntries: cfg.HistoryMaxEntries,
+		BrowserPriority:   cfg.BrowserPriority,
+		URLNormalization:  cfg.URLNormalization,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the configuration: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// bookmarkCachePath returns a path of the cache of the key
+func bookmarkCachePath(key string) string {
+	return filepath.Join(awf.GetCacheDir(), key+".gob"+cacheSuffix)
+}
+
+// loadBookmarkCache returns cached bookmarks if the ttl does not expire and neither the configuration nor bookmark files are changed
+func loadBookmarkCache(key string, ttl time.Duration, configHash string, fps bookmarker.Fingerprints) (bookmarker.Bookmarks, error) {
+	f, err := os.Open(bookmarkCachePath(key))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := gob.NewDecoder(f)
+	var version int
+	if err := dec.Decode(&version); err != nil {
+		return nil, fmt.Errorf("failed to load the cache: %w", err)
+	}
+	if version != cacheVersion {
+		return nil, fmt.Errorf("%w from %d to %d", errCacheVersion, version, cacheVersion)
+	}
+
+	c := new(bookmarkCache)
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("failed to load the cache: %w", err)
+	}
+	if time.Since(c.CreatedAt) > ttl {
+		return nil, errCacheExpired
+	}
+	if c.ConfigHash != configHash {
+		return nil, errCacheConfig
+	}
+	if !fps.Equal(c.Fingerprints) &&
+		(time.Since(c.CreatedAt) > volatileCacheAge || !fps.EqualExceptVolatile(c.Fingerprints)) {
+		return nil, errCacheChanged
+	}
+	return c.Bookmarks, nil
+}
+
+// storeBookmarkCache saves bookmarks with the hash of the configuration and the fingerprints.
+// The cache is replaced atomically not to be read while writing
+func storeBookmarkCache(key string, bookmarks bookmarker.Bookmarks, configHash string, fps bookmarker.Fingerprints) error {
+	path := bookmarkCachePath(key)
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	enc := gob.NewEncoder(f)
+	c := &bookmarkCache{
+		CreatedAt:    time.Now(),
+		ConfigHash:   configHash,
+		Fingerprints: fps,
+		Bookmarks:    bookmarks,
+	}
+	if err := enc.Encode(cacheVersion); err != nil {
+		f.Close()
+		return err
+	}
+	if err := enc.Encode(c); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// clearBookmarkCache removes the cache
+func clearBookmarkCache(key string) error {
+	err := os.Remove(bookmarkCachePath(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
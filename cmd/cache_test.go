@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"encoding/gob"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+func Test_cacheConfigHash(t *testing.T) {
+	hash := func(cfg *Config) string {
+		t.Helper()
+		h, err := cacheConfigHash(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	cfg := &Config{
+		Sources:          defaultSourceConfigs(),
+		RemoveDuplicates: true,
+		URLNormalization: bookmarker.NewURLNormalizer(),
+		SearchWeights:    bookmarker.NewSearchWeights(),
+	}
+	before := hash(cfg)
+
+	cfg.SearchWeights.Title = 2
+	cfg.MaxCacheAge = 1
+	if got := hash(cfg); got != before {
+		t.Errorf("options of rendering should not change the hash")
+	}
+	cfg.URLNormalization.IgnoreWWW = false
+	if got := hash(cfg); got == before {
+		t.Errorf("url normalization should change the hash")
+	}
+	cfg.URLNormalization.IgnoreWWW = true
+	cfg.Sources["chrome"].(*bookmarker.ProfileConfig).History = true
+	if got := hash(cfg); got == before {
+		t.Errorf("source configurations should change the hash")
+	}
+}
+
+func TestBookmarkCache(t *testing.T) {
+	key := "test-bookmarks"
+	bookmarks := bookmarker.Bookmarks{
+		&bookmarker.Bookmark{
+			BookmarkerName: bookmarker.Chrome,
+			Profile:        "Default",
+			Folder:         "/Bookmarks Bar",
+			Title:          "Google",
+			Domain:         "www.google.com",
+			URI:            "https://www.google.com/",
+			ID:             "065efd70-9e6d-4048-b931-8c9d64af196a",
+			Added:          time.Date(2019, 12, 9, 14, 8, 23, 0, time.UTC),
+			Tags:           []string{"search"},
+		},
+	}
+	fps := bookmarker.Fingerprints{
+		{Path: "/path/to/Bookmarks", Size: 10, ModTime: 1, Hash: "hash"},
+	}
+	if err := clearBookmarkCache(key); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = clearBookmarkCache(key)
+	})
+
+	if _, err := loadBookmarkCache(key, time.Hour, "config", fps); err == nil {
+		t.Errorf("expect error happens if the cache does not exist")
+	}
+
+	if err := storeBookmarkCache(key, bookmarks, "config", fps); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadBookmarkCache(key, time.Hour, "config", fps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// metadata of bookmarks is kept
+	if diff := cmp.Diff(bookmarks, got); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+
+	if _, err := loadBookmarkCache(key, 0, "config", fps); !errors.Is(err, errCacheExpired) {
+		t.Errorf("want %v, got %v", errCacheExpired, err)
+	}
+
+	if _, err := loadBookmarkCache(key, time.Hour, "new-config", fps); !errors.Is(err, errCacheConfig) {
+		t.Errorf("want %v, got %v", errCacheConfig, err)
+	}
+
+	changed := bookmarker.Fingerprints{
+		{Path: "/path/to/Bookmarks", Size: 11, ModTime: 2, Hash: "new-hash"},
+	}
+	if _, err := loadBookmarkCache(key, time.Hour, "config", changed); !errors.Is(err, errCacheChanged) {
+		t.Errorf("want %v, got %v", errCacheChanged, err)
+	}
+
+	// changes of volatile files are ignored while the cache is young
+	volatile := bookmarker.Fingerprints{
+		{Path: "/path/to/places.sqlite", Size: 10, ModTime: 1, Volatile: true},
+	}
+	if err := storeBookmarkCache(key, bookmarks, "config", volatile); err != nil {
+		t.Fatal(err)
+	}
+	visited := bookmarker.Fingerprints{
+		{Path: "/path/to/places.sqlite", Size: 12, ModTime: 2, Volatile: true},
+	}
+	if _, err := loadBookmarkCache(key, time.Hour, "config", visited); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	writeTestBookmarkCache(t, key, &bookmarkCache{
+		CreatedAt:    time.Now().Add(-volatileCacheAge - time.Minute),
+		ConfigHash:   "config",
+		Fingerprints: volatile,
+		Bookmarks:    bookmarks,
+	})
+	if _, err := loadBookmarkCache(key, time.Hour, "config", visited); !errors.Is(err, errCacheChanged) {
+		t.Errorf("want %v, got %v", errCacheChanged, err)
+	}
+
+	// a cache of an old format is ignored
+	f, err := os.Create(bookmarkCachePath(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gob.NewEncoder(f).Encode(cacheVersion - 1); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := loadBookmarkCache(key, time.Hour, "config", fps); !errors.Is(err, errCacheVersion) {
+		t.Errorf("want %v, got %v", errCacheVersion, err)
+	}
+}
+
+// writeTestBookmarkCache writes the cache of the current version as it is
+func writeTestBookmarkCache(t *testing.T, key string, c *bookmarkCache) {
+	t.Helper()
+	f, err := os.Create(bookmarkCachePath(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := gob.NewEncoder(f)
+	if err := enc.Encode(cacheVersion); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(c); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,9 +25,11 @@ var (
 const (
 	emptyTitle    = "No matching"
 	emptySubtitle = ""
-	firefoxImage  = "firefox.png"
-	chromeImage   = "chrome.png"
-	safariImage   = "safari.png"
+	cacheSuffix   = "-alfred-bookmarks.cache"
+	// historyMark marks history entries in subtitles
+	historyMark = "🕘 History"
+	// templateMark marks url templates which take the rest of the query
+	templateMark = "🔎 Template"
 )
 
 func init() {
@@ -35,7 +40,7 @@ func init() {
 			version,
 			14*24*time.Hour,
 		),
-		alfred.WithCacheSuffix("-alfred-bookmarks.cache"),
+		alfred.WithCacheSuffix(cacheSuffix),
 		alfred.WithOutWriter(os.Stdout),
 		alfred.WithLogWriter(os.Stderr),
 		alfred.WithInitializers(
@@ -49,9 +54,21 @@ func init() {
 
 type runtime struct {
 	cfg           *Config
-	query         string
-	folderPrefixF func(subtitle string) bool
+	query         *query
+	folderPrefixF func(b *bookmarker.Bookmark) bool
 	clear         bool
+	resetUsage    bool
+	tabsOnly      bool
+}
+
+// subcommands are invoked with the first argument. search is the default one
+var subcommands = map[string]func(cfg *Config, args ...string){
+	"search":      search,
+	"open":        open,
+	"export":      export,
+	"record":      record,
+	"add":         add,
+	"diagnostics": diagnostics,
 }
 
 // Execute runs cmd
@@ -61,6 +78,17 @@ func Execute(args ...string) {
 		awf.Fatal("a fatal error occurred", err.Error())
 	}
 
+	if len(args) > 0 {
+		if subcommand, ok := subcommands[args[0]]; ok {
+			subcommand(cfg, args[1:]...)
+			return
+		}
+	}
+	search(cfg, args...)
+}
+
+// search runs script filter
+func search(cfg *Config, args ...string) {
 	r, err := parse(cfg, args...)
 	if err != nil {
 		awf.Clear().Append(
@@ -72,6 +100,18 @@ func Execute(args ...string) {
 				Title("--clear option: clear existing cache data").
 				Icon(awf.Assets().IconAlertNote()).
 				Valid(false),
+			alfred.NewItem().
+				Title("--reset-usage option: forget opened bookmarks used for ranking").
+				Icon(awf.Assets().IconAlertNote()).
+				Valid(false),
+			alfred.NewItem().
+				Title("--tabs option: list only open tabs in order of windows and tabs").
+				Icon(awf.Assets().IconAlertNote()).
+				Valid(false),
+			alfred.NewItem().
+				Title("query: filter by domain:, folder:, browser:, tag:, url:, is:unread, \"phrase\" and -term").
+				Icon(awf.Assets().IconAlertNote()).
+				Valid(false),
 		).Output()
 		return
 	}
@@ -80,111 +120,449 @@ func Execute(args ...string) {
 
 func parse(cfg *Config, args ...string) (*runtime, error) {
 	var folderPrefix string
-	var clear bool
+	var clear, resetUsage, tabsOnly bool
 	fs := flag.NewFlagSet("bs", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	fs.StringVarP(&folderPrefix, "folder", "f", "", "filter by folder")
 	fs.BoolVar(&clear, "clear", false, "clear cache")
-	if err := fs.Parse(args); err != nil {
+	fs.BoolVar(&resetUsage, "reset-usage", false, "reset usage of bookmarks")
+	fs.BoolVar(&tabsOnly, "tabs", false, "list only open tabs")
+	flagArgs, words := splitFlagArgs(fs, args)
+	if err := fs.Parse(flagArgs); err != nil {
 		return nil, err
 	}
+	words = append(words, fs.Args()...)
 	r := &runtime{
 		cfg:           cfg,
-		query:         strings.Join(fs.Args(), " "),
-		folderPrefixF: filterBySubtitle(folderPrefix),
+		query:         parseQuery(strings.Join(words, " ")),
+		folderPrefixF: filterByFolder(folderPrefix),
 		clear:         clear,
+		resetUsage:    resetUsage,
+		tabsOnly:      tabsOnly,
 	}
 	return r, nil
 }
 
+// splitFlagArgs separates arguments of the flags from words of the query.
+// Note: unknown flags like `-term` are exclusions of the query
+func splitFlagArgs(fs *flag.FlagSet, args []string) (flagArgs, words []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var f *flag.Flag
+		switch {
+		case arg == "--":
+			return append(flagArgs, args[i:]...), words
+		case strings.HasPrefix(arg, "--"):
+			name, _, _ := strings.Cut(arg[2:], "=")
+			f = fs.Lookup(name)
+		case len(arg) == 2 && arg[0] == '-':
+			f = fs.ShorthandLookup(arg[1:])
+		}
+		if f == nil {
+			words = append(words, arg)
+			continue
+		}
+
+		flagArgs = append(flagArgs, arg)
+		// a value of the flag follows it
+		if f.Value.Type() != "bool" && !strings.Contains(arg, "=") && i+1 < len(args) {
+			i++
+			flagArgs = append(flagArgs, args[i])
+		}
+	}
+	return flagArgs, words
+}
+
 func (r *runtime) run() error {
 	cacheKey := "bookmarks"
 	if r.clear {
-		if err := awf.Cache(cacheKey).ClearItems().Err(); err != nil {
+		if err := clearBookmarkCache(cacheKey); err != nil {
 			awf.Logger().Warnln(err.Error())
 		} else {
 			awf.Logger().Infoln("cache cleared!")
 		}
 	}
+	if r.resetUsage {
+		if err := clearUsage(); err != nil {
+			awf.Logger().Warnln(err.Error())
+		} else {
+			awf.Logger().Infoln("usage reset!")
+		}
+	}
+
+	manager, err := newManager(r.cfg)
+	if err != nil {
+		return err
+	}
 
+	// Note: the cache is available until the ttl expires, the configuration or any bookmark file changes
+	fps, fpErr := bookmarker.GetFingerprints(manager)
+	if fpErr != nil {
+		awf.Logger().Warnln(fpErr.Error())
+	}
+	configHash, hashErr := cacheConfigHash(r.cfg)
+	if hashErr != nil {
+		awf.Logger().Warnln(hashErr.Error())
+	}
 	ttl := convertDefaultTTL(r.cfg.MaxCacheAge)
-	if awf.Cache(cacheKey).LoadItems(ttl).Err() == nil {
+	bookmarks, err := loadBookmarkCache(cacheKey, ttl, configHash, fps)
+	if err == nil && fpErr == nil && hashErr == nil {
 		awf.Logger().Infoln("loading from cache file")
-		awf.FilterByItemProperty(r.folderPrefixF, alfred.ItemPropertySubtitle).
-			Filter(r.query).Output()
+		r.render(bookmarks)
+		return nil
+	}
+	awf.Logger().Infof("rebuilding the cache: %s\n", err)
+
+	bookmarks, err = manager.Bookmarks()
+	var loadErrs bookmarker.LoadErrors
+	if err != nil && !errors.As(err, &loadErrs) {
+		return err
+	}
+	logLoadedFiles(manager)
+	// show failed sources as warnings and search bookmarks of the others
+	for _, e := range loadErrs {
+		awf.SetSystemInfo(
+			alfred.NewItem().
+				Title(fmt.Sprintf("failed to load %s bookmarks", sourceName(string(e.BookmarkerName), e.Profile))).
+				Subtitle(e.Err.Error()).
+				Icon(awf.Assets().IconCaution()).
+				Valid(false),
+		)
+	}
+	r.render(bookmarks)
+
+	if len(loadErrs) > 0 {
+		// Note: do not store partial results so that failed sources are retried next time
+		awf.Logger().Warnln(loadErrs.Error())
 		return nil
 	}
+	if err := storeBookmarkCache(cacheKey, bookmarks, configHash, fps); err != nil {
+		awf.Logger().Warnln(err.Error())
+	}
+	return nil
+}
 
-	opts := make([]bookmarker.Option, 0, 4)
-	if r.cfg.Firefox.Enable {
-		opts = append(opts, bookmarker.WithFirefox(r.cfg.Firefox.ProfilePath, r.cfg.Firefox.ProfileName))
+// render outputs items of the bookmarks which match the query
+func (r *runtime) render(bookmarks bookmarker.Bookmarks) {
+	multiProfiles := hasMultiProfiles(bookmarks)
+	text := r.query.text()
+	for _, result := range r.search(bookmarks) {
+		b := result.Bookmark
+		if !r.folderPrefixF(b) {
+			continue
+		}
+		// use the site icon and fall back to the browser icon
+		image := b.Icon
+		if s, ok := bookmarker.LookupSource(string(b.BookmarkerName)); ok && image == "" {
+			image = s.Icon
+		}
+		item := alfred.NewItem().
+			Title(b.Title).
+			Autocomplete(b.Title).
+			Arg(b.URI).
+			Icon(
+				alfred.NewIcon().
+					Path(image),
+			).
+			Variable("nextAction", "open").
+			// record the selection with the query to rank the bookmark next time
+			Variable("query", text)
+		subtitle := fmt.Sprintf("[%s] %s", b.Folder, b.Domain)
+		if b.History {
+			// Note: history has no folder, so the folder filter excludes it
+			subtitle = fmt.Sprintf("%s %s · visited %s", historyMark, b.Domain, b.LastVisited.Local().Format("2006-01-02"))
+		}
+		if b.ReadingList {
+			subtitle = readingListSubtitle(subtitle, b)
+		}
+		if b.Tab != nil {
+			subtitle = fmt.Sprintf("%s · tab %d", subtitle, b.Tab.Index)
+		}
+		if b.IsTemplate() {
+			subtitle = r.renderTemplate(item, subtitle, b)
+		}
+		if multiProfiles[string(b.BookmarkerName)] {
+			// open the bookmark with the profile which it comes from
+			subtitle = fmt.Sprintf("%s (%s)", subtitle, b.Profile)
+			item.Variable("nextAction", "open-profile").
+				Variable("browser", string(b.BookmarkerName)).
+				Variable("profile", b.Profile)
+		}
+		if badges := originBadges(b); badges != "" {
+			subtitle = fmt.Sprintf("%s | %s", subtitle, badges)
+		}
+		if result.Field != "" && result.Field != bookmarker.SearchFieldTitle {
+			// tell why the bookmark matches if the title does not match
+			subtitle = fmt.Sprintf("%s | matched %s", subtitle, result.Field)
+		}
+		item.Subtitle(subtitle)
+		awf.Append(item)
 	}
-	if r.cfg.Chrome.Enable {
-		opts = append(opts, bookmarker.WithChrome(r.cfg.Chrome.ProfilePath, r.cfg.Chrome.ProfileName))
+
+	awf.Output()
+}
+
+// renderTemplate makes the item open the url template with the argument of the query and returns the subtitle.
+// The item is not valid without the argument and the keyword is completed to type it
+func (r *runtime) renderTemplate(item *alfred.Item, subtitle string, b *bookmarker.Bookmark) string {
+	completion := b.Keyword
+	if completion == "" {
+		completion = b.Title
 	}
-	if r.cfg.Safari.Enable {
-		opts = append(opts, bookmarker.WithSafari())
+	item.Autocomplete(completion + " ")
+
+	// Note: a template which matches the whole query but is not selected by the first word is not expanded
+	head, arg := r.templateArgs()
+	if arg == "" || !b.SelectedBy(head) {
+		item.Valid(false)
+		return fmt.Sprintf("%s · %s: type %s and a query", subtitle, templateMark, completion)
 	}
+	item.Title(fmt.Sprintf("%s: %s", b.Title, arg)).
+		Arg(b.Expand(arg))
+	return fmt.Sprintf("%s · %s", subtitle, templateMark)
+}
 
-	if r.cfg.RemoveDuplicates {
-		opts = append(opts, bookmarker.WithRemoveDuplicates())
+// readingListSubtitle appends the status and the preview text of the reading list item
+func readingListSubtitle(subtitle string, b *bookmarker.Bookmark) string {
+	if b.Unread {
+		subtitle += " · unread"
+	}
+	if !b.Added.IsZero() {
+		subtitle = fmt.Sprintf("%s · added %s", subtitle, b.Added.Local().Format("2006-01-02"))
 	}
+	if b.Description != "" {
+		subtitle = fmt.Sprintf("%s · %s", subtitle, b.Description)
+	}
+	return subtitle
+}
 
-	manager, err := bookmarker.New(opts...)
-	if err != nil {
-		return err
+// search returns bookmarks which match the query in order of scores
+func (r *runtime) search(bookmarks bookmarker.Bookmarks) []*bookmarker.SearchResult {
+	filtered := make(bookmarker.Bookmarks, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if r.tabsOnly && b.Tab == nil {
+			continue
+		}
+		if r.query.match(b) {
+			filtered = append(filtered, b)
+		}
 	}
 
-	bookmarks, err := manager.Bookmarks()
+	text := r.query.text()
+	var results []*bookmarker.SearchResult
+	head, _ := r.templateArgs()
+	if text != "" {
+		results = filtered.Search(text, r.cfg.SearchWeights)
+		if head != "" {
+			results = appendTemplates(results, filtered, head, r.cfg.SearchWeights)
+		}
+	} else {
+		if r.tabsOnly {
+			sortByTabs(filtered)
+		} else if r.cfg.Visits {
+			filtered.SortByVisits()
+		}
+		results = make([]*bookmarker.SearchResult, 0, len(filtered))
+		for _, b := range filtered {
+			results = append(results, &bookmarker.SearchResult{Bookmark: b})
+		}
+		if r.tabsOnly {
+			// keep positions of the tabs
+			return results
+		}
+	}
+
+	u, err := loadUsage()
 	if err != nil {
-		return err
+		awf.Logger().Warnln(err.Error())
+		u = new(usageStore)
+	}
+	// boost frequently and recently opened bookmarks. Note: scores are zero if the query has no text
+	boosts := u.frecency(text, time.Now())
+	rank := func(r *bookmarker.SearchResult) float64 {
+		return (r.Score + 1) * (1 + boosts[r.Bookmark.URI])
 	}
+	sort.SliceStable(results, func(i, j int) bool {
+		hi, hj := results[i].Bookmark.History, results[j].Bookmark.History
+		if hi != hj && !r.cfg.MixHistory {
+			// real bookmarks come first
+			return hj
+		}
+		return rank(results[i]) > rank(results[j])
+	})
+	return withKeywords(results, filtered, text, head)
+}
+
+// templateArgs splits free text of the query into the first word which selects a template and the argument of it.
+// Both are empty unless the query has an argument
+func (r *runtime) templateArgs() (head, arg string) {
+	words := r.query.words()
+	if len(words) < 2 {
+		return "", ""
+	}
+	return strings.ToLower(words[0]), strings.Join(words[1:], " ")
+}
 
+// appendTemplates appends templates which the first word selects as the rest of the query is the argument of them
+func appendTemplates(results []*bookmarker.SearchResult, bookmarks bookmarker.Bookmarks, head string, w *bookmarker.SearchWeights) []*bookmarker.SearchResult {
+	found := make(map[*bookmarker.Bookmark]bool, len(results))
+	for _, r := range results {
+		found[r.Bookmark] = true
+	}
+	templates := make(bookmarker.Bookmarks, 0)
 	for _, b := range bookmarks {
-		var image string
-		switch b.BookmarkerName {
-		case bookmarker.Firefox:
-			image = firefoxImage
-		case bookmarker.Chrome:
-			image = chromeImage
-		case bookmarker.Safari:
-			image = safariImage
-		}
-		awf.Append(
-			alfred.NewItem().
-				Title(b.Title).
-				Subtitle(fmt.Sprintf("[%s] %s", b.Folder, b.Domain)).
-				Autocomplete(b.Title).
-				Arg(b.URI).
-				Icon(
-					alfred.NewIcon().
-						Path(image),
-				).
-				Variable("nextAction", "open"),
-		)
+		if b.SelectedBy(head) && !found[b] {
+			templates = append(templates, b)
+		}
+	}
+	return append(results, templates.Search(head, w)...)
+}
+
+// withKeywords moves bookmarks whose keywords are the query to the top like the address bar of firefox.
+// A keyword of a template is the first word of the query which is followed by the argument
+func withKeywords(results []*bookmarker.SearchResult, bookmarks bookmarker.Bookmarks, text, head string) []*bookmarker.SearchResult {
+	if text == "" {
+		return results
+	}
+	ret := make([]*bookmarker.SearchResult, 0, len(results)+1)
+	matched := make(map[*bookmarker.Bookmark]bool)
+	for _, b := range bookmarks {
+		if b.Keyword == "" {
+			continue
+		}
+		if strings.EqualFold(b.Keyword, text) || (head != "" && b.IsTemplate() && strings.EqualFold(b.Keyword, head)) {
+			ret = append(ret, &bookmarker.SearchResult{Bookmark: b, Field: bookmarker.SearchFieldKeyword})
+			matched[b] = true
+		}
+	}
+	if len(matched) == 0 {
+		return results
+	}
+
+	for _, r := range results {
+		if !matched[r.Bookmark] {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// sortByTabs sorts open tabs in order of windows and tabs of each profile
+func sortByTabs(bookmarks bookmarker.Bookmarks) {
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		ti, tj := bookmarks[i].Tab, bookmarks[j].Tab
+		if bookmarks[i].Profile != bookmarks[j].Profile {
+			return bookmarks[i].Profile < bookmarks[j].Profile
+		}
+		if ti.Window != tj.Window {
+			return ti.Window < tj.Window
+		}
+		return ti.Index < tj.Index
+	})
+}
+
+// faviconDir returns a directory of the per-domain icon cache
+func faviconDir() string {
+	return filepath.Join(awf.GetDataDir(), "favicons")
+}
+
+// newManager returns a bookmarker of enabled sources in the configuration
+func newManager(cfg *Config) (bookmarker.Bookmarker, error) {
+	opts := make([]bookmarker.Option, 0, len(cfg.Sources)+1)
+	for _, s := range bookmarker.Sources() {
+		if c, ok := cfg.Sources[s.Name]; ok && c.Enabled() {
+			opts = append(opts, bookmarker.WithSource(s.Name, c))
+		}
 	}
 
-	defer func() {
-		awf.FilterByItemProperty(r.folderPrefixF, alfred.ItemPropertySubtitle).
-			Filter(r.query).Output()
-	}()
-	return awf.Cache(cacheKey).StoreItems().Err()
+	if cfg.RemoveDuplicates {
+		opts = append(opts, bookmarker.WithRemoveDuplicates())
+	}
+	if cfg.Visits {
+		opts = append(opts, bookmarker.WithVisits())
+	}
+	if cfg.Favicons {
+		opts = append(opts, bookmarker.WithFavicons(faviconDir()))
+	}
+	if cfg.HistoryMaxAgeDays != 0 || cfg.HistoryMaxEntries != 0 {
+		// Note: a minus value means no limit
+		maxAge := time.Duration(cfg.HistoryMaxAgeDays) * 24 * time.Hour
+		opts = append(opts, bookmarker.WithHistoryLimit(maxAge, cfg.HistoryMaxEntries))
+	}
+	if len(cfg.BrowserPriority) > 0 {
+		opts = append(opts, bookmarker.WithBrowserPriority(cfg.BrowserPriority...))
+	}
+	if cfg.URLNormalization != nil {
+		opts = append(opts, bookmarker.WithURLNormalizer(cfg.URLNormalization))
+	}
+
+	return bookmarker.New(opts...)
 }
 
-func filterBySubtitle(prefixQuery string) func(subtitle string) bool {
-	f := func(subtitle string) bool {
+// sourceName returns a display name of a bookmark source with the profile
+func sourceName(name, profile string) string {
+	if s, ok := bookmarker.LookupSource(name); ok && s.DisplayName != "" {
+		name = s.DisplayName
+	}
+	if profile == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, profile)
+}
+
+// originBadges returns badges of browsers which have the merged bookmark
+func originBadges(b *bookmarker.Bookmark) string {
+	if len(b.Origins) < 2 {
+		return ""
+	}
+
+	badges := make([]string, 0, len(b.Origins))
+	seen := make(map[string]bool)
+	for _, o := range b.Origins {
+		name := string(o.BookmarkerName)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		badge := name
+		if s, ok := bookmarker.LookupSource(name); ok && s.Badge != "" {
+			badge = s.Badge
+		}
+		badges = append(badges, badge)
+	}
+	return strings.Join(badges, " ")
+}
+
+// hasMultiProfiles returns browsers whose bookmarks come from more than one profile
+func hasMultiProfiles(bookmarks bookmarker.Bookmarks) map[string]bool {
+	profiles := make(map[string]map[string]bool)
+	for _, b := range bookmarks {
+		name := string(b.BookmarkerName)
+		if profiles[name] == nil {
+			profiles[name] = make(map[string]bool)
+		}
+		profiles[name][b.Profile] = true
+	}
+
+	ret := make(map[string]bool)
+	for name, p := range profiles {
+		ret[name] = len(p) > 1
+	}
+	return ret
+}
+
+// filterByFolder returns a filter of bookmarks in the folder.
+// Note: the folder of the bookmark is compared as subtitles have other texts like preview texts of reading lists
+func filterByFolder(prefixQuery string) func(b *bookmarker.Bookmark) bool {
+	f := func(b *bookmarker.Bookmark) bool {
 		// Note: if input is empty return true
 		if prefixQuery == "" {
 			return true
 		}
-
-		leftTrimedSubtitle := strings.TrimLeft(subtitle, "[")
-		idx := strings.LastIndex(leftTrimedSubtitle, "]")
-		if idx < 0 {
+		if b.History {
 			return false
 		}
-		folder := leftTrimedSubtitle[:idx]
-		return hasFolderPrefix(folder, prefixQuery)
+		return hasFolderPrefix(b.Folder, prefixQuery)
 	}
 	return f
 }
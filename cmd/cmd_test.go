@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
 	"github.com/konoui/go-alfred"
 	"github.com/konoui/go-alfred/initialize"
 )
@@ -14,6 +18,7 @@ import (
 const testdataPath = "testdata"
 
 func TestRun(t *testing.T) {
+	brokenChromeProfilePath := createBrokenChromeProfile(t)
 	type args struct {
 		query  string
 		folder string
@@ -30,10 +35,8 @@ func TestRun(t *testing.T) {
 			name: "enbale only firefox",
 			config: &Config{
 				MaxCacheAge: -1,
-				Firefox: Firefox{
-					Enable:      true,
-					ProfileName: firefoxDefaultProfileName,
-					ProfilePath: firefoxDefaultProfilePath,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
 				},
 			},
 			filepath: filepath.Join(testdataPath, "test-firefox.json"),
@@ -42,10 +45,8 @@ func TestRun(t *testing.T) {
 			name: "enbale only chrome",
 			config: &Config{
 				MaxCacheAge: -1,
-				Chrome: Chrome{
-					Enable:      true,
-					ProfileName: chromeDefaultProfileName,
-					ProfilePath: chromeDefaultProfilePath,
+				Sources: map[string]bookmarker.SourceConfig{
+					"chrome": testProfileConfig("chrome"),
 				},
 			},
 			filepath: filepath.Join(testdataPath, "test-chrome.json"),
@@ -54,32 +55,128 @@ func TestRun(t *testing.T) {
 			name: "enbale only safari",
 			config: &Config{
 				MaxCacheAge: -1,
-				Safari: Safari{
-					Enable: true,
+				Sources: map[string]bookmarker.SourceConfig{
+					"safari": &bookmarker.SafariConfig{Enable: true},
 				},
 			},
 			filepath: filepath.Join(testdataPath, "test-safari.json"),
 		},
+		{
+			name: "enable all chrome profiles. subtitle has a profile name and open action targets the profile",
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"chrome": &bookmarker.ProfileConfig{
+						Enable:      true,
+						AllProfiles: true,
+						ProfilePath: testProfileConfig("chrome").ProfilePath,
+					},
+				},
+			},
+			filepath: filepath.Join(testdataPath, "test-chrome-all-profiles.json"),
+		},
+		{
+			name: "enbale only brave",
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"brave": testProfileConfig("brave"),
+				},
+			},
+			filepath: filepath.Join(testdataPath, "test-brave.json"),
+		},
+		{
+			name: "enbale only opera",
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"opera": testProfileConfig("opera"),
+				},
+			},
+			filepath: filepath.Join(testdataPath, "test-opera.json"),
+		},
 		{
 			name: "enable firefox, chrome, safari. duplicate bookmarks should be removed ",
 			config: &Config{
 				RemoveDuplicates: true,
 				MaxCacheAge:      -1,
-				Firefox: Firefox{
-					Enable:      true,
-					ProfileName: firefoxDefaultProfileName,
-					ProfilePath: firefoxDefaultProfilePath,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
+					"chrome":  testProfileConfig("chrome"),
+					"safari":  &bookmarker.SafariConfig{Enable: true},
 				},
-				Chrome: Chrome{
-					Enable:      true,
-					ProfileName: chromeDefaultProfileName,
-					ProfilePath: chromeDefaultProfilePath,
+			},
+			filepath: filepath.Join(testdataPath, "test-rm-duplicate-firefox-chrome-safari.json"),
+		},
+		{
+			name: "enable firefox, chrome, safari with browser priority. safari bookmarks should win",
+			config: &Config{
+				RemoveDuplicates: true,
+				BrowserPriority:  []string{"safari", "firefox"},
+				MaxCacheAge:      -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
+					"chrome":  testProfileConfig("chrome"),
+					"safari":  &bookmarker.SafariConfig{Enable: true},
 				},
-				Safari: Safari{
-					Enable: true,
+			},
+			filepath: filepath.Join(testdataPath, "test-browser-priority.json"),
+		},
+		{
+			name: "chrome bookmark is broken. show a warning and firefox bookmarks",
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
+					"chrome": &bookmarker.ProfileConfig{
+						Enable:      true,
+						ProfileName: testProfileConfig("chrome").ProfileName,
+						ProfilePath: brokenChromeProfilePath,
+					},
 				},
 			},
-			filepath: filepath.Join(testdataPath, "test-rm-duplicate-firefox-chrome-safari.json"),
+			filepath: filepath.Join(testdataPath, "test-partial-failure.json"),
+		},
+		{
+			name: "chrome profile does not exist. show a warning and firefox bookmarks",
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
+					"chrome": &bookmarker.ProfileConfig{
+						Enable:      true,
+						ProfileName: testProfileConfig("chrome").ProfileName,
+						ProfilePath: "/nonexistent/Google/Chrome",
+					},
+				},
+			},
+			filepath: filepath.Join(testdataPath, "test-missing-profile.json"),
+		},
+		{
+			name: "filter by fields and exclusion. return chrome",
+			args: args{
+				query: "folder:1-hierarchy-b -twitter",
+			},
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"chrome": testProfileConfig("chrome"),
+				},
+			},
+			filepath: filepath.Join(testdataPath, "test-query-fields.json"),
+		},
+		{
+			name: "search urls. return a chrome bookmark matched by the url",
+			args: args{
+				query: "login",
+			},
+			config: &Config{
+				MaxCacheAge: -1,
+				Sources: map[string]bookmarker.SourceConfig{
+					"chrome": testProfileConfig("chrome"),
+				},
+			},
+			filepath: filepath.Join(testdataPath, "test-search-url.json"),
 		},
 		{
 			name: "pass flag format argument. no errors should occur",
@@ -98,18 +195,10 @@ func TestRun(t *testing.T) {
 			config: &Config{
 				RemoveDuplicates: false,
 				MaxCacheAge:      -1,
-				Firefox: Firefox{
-					Enable:      true,
-					ProfileName: firefoxDefaultProfileName,
-					ProfilePath: firefoxDefaultProfilePath,
-				},
-				Chrome: Chrome{
-					Enable:      true,
-					ProfileName: chromeDefaultProfileName,
-					ProfilePath: chromeDefaultProfilePath,
-				},
-				Safari: Safari{
-					Enable: true,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
+					"chrome":  testProfileConfig("chrome"),
+					"safari":  &bookmarker.SafariConfig{Enable: true},
 				},
 			},
 			filepath: filepath.Join(testdataPath, "test-firefox.json"),
@@ -135,8 +224,8 @@ func TestRun(t *testing.T) {
 
 			r := &runtime{
 				cfg:           tt.config,
-				query:         tt.args.query,
-				folderPrefixF: filterBySubtitle(tt.args.folder),
+				query:         parseQuery(tt.args.query),
+				folderPrefixF: filterByFolder(tt.args.folder),
 			}
 
 			exitCode := awf.RunSimple(r.run)
@@ -164,6 +253,69 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRunCache(t *testing.T) {
+	profilePath := t.TempDir()
+	dir := filepath.Join(profilePath, "Default")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	bookmarkFile := filepath.Join(dir, "Bookmarks")
+	data, err := os.ReadFile(filepath.Join("..", "pkg", "bookmarker", "testdata", "test-chrome-bookmarks-checksum.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bookmarkFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{
+		Sources: map[string]bookmarker.SourceConfig{
+			"chrome": &bookmarker.ProfileConfig{
+				Enable:      true,
+				ProfileName: "Default",
+				ProfilePath: profilePath,
+			},
+		},
+	}
+	// run returns logs and outputs of the workflow
+	run := func(clear bool) (string, string) {
+		outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+		awf = alfred.NewWorkflow(
+			alfred.WithLogWriter(errBuf),
+			alfred.WithOutWriter(outBuf),
+		)
+		r := &runtime{
+			cfg:           cfg,
+			query:         parseQuery(""),
+			folderPrefixF: filterByFolder(""),
+			clear:         clear,
+		}
+		if exitCode := awf.RunSimple(r.run); exitCode != 0 {
+			t.Fatalf("unexpected error happens %d: %s", exitCode, errBuf.String())
+		}
+		return errBuf.String(), outBuf.String()
+	}
+
+	if logs, _ := run(true); strings.Contains(logs, "loading from cache file") {
+		t.Errorf("cache should be cleared")
+	}
+	if logs, _ := run(false); !strings.Contains(logs, "loading from cache file") {
+		t.Errorf("cache should be used if bookmark files are not changed")
+	}
+
+	// Note: the checksum of the file is verified, so change the file in the same way as chrome
+	if _, err := bookmarker.AddChromeBookmark(bookmarkFile, "", "Google Search", "https://www.google.com/search"); err != nil {
+		t.Fatal(err)
+	}
+	logs, out := run(false)
+	if strings.Contains(logs, "loading from cache file") {
+		t.Errorf("cache should be rebuilt if bookmark files are changed")
+	}
+	if !strings.Contains(out, "Google Search") {
+		t.Errorf("new bookmark title should be found: %s", out)
+	}
+}
+
 func Test_parse(t *testing.T) {
 	type args struct {
 		args []string
@@ -192,6 +344,18 @@ func Test_parse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "exclusion terms are not flags",
+			args: args{
+				[]string{
+					"github",
+					"-draft",
+					"--clear",
+					"-f",
+					"Bookmark menu",
+				},
+			},
+		},
 		{
 			name: "flag parse",
 			args: args{
@@ -226,3 +390,197 @@ func writeFile(filename string, data []byte) error {
 	}
 	return nil
 }
+
+// createBrokenChromeProfile creates a chrome profile path whose bookmark file is broken
+func createBrokenChromeProfile(t *testing.T) string {
+	t.Helper()
+	profilePath := t.TempDir()
+	dir := filepath.Join(profilePath, testProfileConfig("chrome").ProfileName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Bookmarks"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return profilePath
+}
+
+func TestSearchHistory(t *testing.T) {
+	t.Setenv("alfred_workflow_data", t.TempDir())
+	bookmarks := bookmarker.Bookmarks{
+		{BookmarkerName: bookmarker.Chrome, Title: "Go", URI: "https://go.dev/", History: true},
+		{BookmarkerName: bookmarker.Chrome, Title: "Go Packages", URI: "https://pkg.go.dev/"},
+	}
+	titles := func(cfg *Config) []string {
+		r := &runtime{cfg: cfg, query: parseQuery("go")}
+		ret := []string{}
+		for _, result := range r.search(bookmarks) {
+			ret = append(ret, result.Bookmark.Title)
+		}
+		return ret
+	}
+
+	if diff := cmp.Diff([]string{"Go Packages", "Go"}, titles(&Config{})); diff != "" {
+		t.Errorf("history should come after bookmarks -want +got\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Go", "Go Packages"}, titles(&Config{MixHistory: true})); diff != "" {
+		t.Errorf("history should be ranked by scores -want +got\n%s", diff)
+	}
+}
+
+func TestSearchTabs(t *testing.T) {
+	t.Setenv("alfred_workflow_data", t.TempDir())
+	bookmarks := bookmarker.Bookmarks{
+		{BookmarkerName: bookmarker.FirefoxTabs, Title: "Go Packages", URI: "https://pkg.go.dev/", Tab: &bookmarker.Tab{Window: 2, Index: 1}},
+		{BookmarkerName: bookmarker.Firefox, Title: "Go", URI: "https://go.dev/"},
+		{BookmarkerName: bookmarker.FirefoxTabs, Title: "Go Blog", URI: "https://go.dev/blog/", Tab: &bookmarker.Tab{Window: 1, Index: 2}},
+		{BookmarkerName: bookmarker.FirefoxTabs, Title: "Go Playground", URI: "https://go.dev/play/", Tab: &bookmarker.Tab{Window: 1, Index: 1}},
+	}
+	titles := func(args ...string) []string {
+		r, err := parse(&Config{}, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret := []string{}
+		for _, result := range r.search(bookmarks) {
+			ret = append(ret, result.Bookmark.Title)
+		}
+		return ret
+	}
+
+	if diff := cmp.Diff([]string{"Go Playground", "Go Blog", "Go Packages"}, titles("--tabs")); diff != "" {
+		t.Errorf("tabs should be listed in order of windows and tabs -want +got\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Go Blog"}, titles("--tabs", "blog")); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if got := titles(); len(got) != len(bookmarks) {
+		t.Errorf("tabs should be searched with bookmarks %v", got)
+	}
+}
+
+func Test_filterByFolder(t *testing.T) {
+	tests := []struct {
+		name     string
+		folder   string
+		bookmark *bookmarker.Bookmark
+		want     bool
+	}{
+		{
+			name:     "no folder filter",
+			bookmark: &bookmarker.Bookmark{Folder: "/Bookmarks Bar"},
+			want:     true,
+		},
+		{
+			name:     "prefix of the folder ignoring case and spaces",
+			folder:   "bookmarksbar/dev",
+			bookmark: &bookmarker.Bookmark{Folder: "/Bookmarks Bar/Dev/go"},
+			want:     true,
+		},
+		{
+			name:     "other folder",
+			folder:   "/Bookmarks Bar/dev",
+			bookmark: &bookmarker.Bookmark{Folder: "/Other Bookmarks/dev"},
+		},
+		{
+			name:     "preview text which has brackets does not affect the folder",
+			folder:   "/Reading List",
+			bookmark: &bookmarker.Bookmark{Folder: "/Reading List", ReadingList: true, Description: "[draft] notes] of go"},
+			want:     true,
+		},
+		{
+			name:     "history has no folder",
+			folder:   "/",
+			bookmark: &bookmarker.Bookmark{History: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterByFolder(tt.folder)(tt.bookmark); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSearchKeyword(t *testing.T) {
+	t.Setenv("alfred_workflow_data", t.TempDir())
+	bookmarks := bookmarker.Bookmarks{
+		{BookmarkerName: bookmarker.Firefox, Title: "GitHub Search", URI: "https://github.com/search"},
+		{BookmarkerName: bookmarker.Firefox, Title: "GitHub", URI: "https://github.com/", Keyword: "gh"},
+		{BookmarkerName: bookmarker.Firefox, Title: "Go", URI: "https://go.dev/", Keyword: "go"},
+	}
+	search := func(text string) []*bookmarker.SearchResult {
+		r := &runtime{cfg: &Config{}, query: parseQuery(text)}
+		return r.search(bookmarks)
+	}
+
+	results := search("GH")
+	if len(results) == 0 || results[0].Bookmark != bookmarks[1] || results[0].Field != bookmarker.SearchFieldKeyword {
+		t.Errorf("the bookmark of the keyword should come first")
+	}
+	for _, r := range results[1:] {
+		if r.Bookmark == bookmarks[1] {
+			t.Errorf("the bookmark of the keyword is duplicated")
+		}
+	}
+	if results := search("g"); len(results) > 0 && results[0].Field == bookmarker.SearchFieldKeyword {
+		t.Errorf("a part of keywords should not jump")
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	t.Setenv("alfred_workflow_data", t.TempDir())
+	bookmarks := bookmarker.Bookmarks{
+		{BookmarkerName: bookmarker.Firefox, Folder: "/", Title: "Jira", Domain: "jira.example.com", URI: "https://jira.example.com/browse/%s", Keyword: "jira"},
+		{BookmarkerName: bookmarker.Chrome, Folder: "/", Title: "Wikipedia", Domain: "en.wikipedia.org", URI: "https://en.wikipedia.org/w/index.php?search=%s"},
+		{BookmarkerName: bookmarker.Chrome, Folder: "/", Title: "Jira Board", Domain: "jira.example.com", URI: "https://jira.example.com/board"},
+		{BookmarkerName: bookmarker.Chrome, Folder: "/foo bar", Title: "Search", Domain: "search.example.com", URI: "https://search.example.com/?q=%s"},
+	}
+	type item struct {
+		Title        string `json:"title"`
+		Arg          string `json:"arg"`
+		Autocomplete string `json:"autocomplete"`
+		Valid        *bool  `json:"valid"`
+	}
+	render := func(args ...string) []item {
+		t.Helper()
+		outBuf := new(bytes.Buffer)
+		awf = alfred.NewWorkflow(
+			alfred.WithLogWriter(new(bytes.Buffer)),
+			alfred.WithOutWriter(outBuf),
+		)
+		r, err := parse(&Config{}, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.render(bookmarks)
+		out := struct {
+			Items []item `json:"items"`
+		}{}
+		if err := json.Unmarshal(outBuf.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.Items
+	}
+
+	items := render("jira", "PROJ-123")
+	if len(items) == 0 || items[0].Title != "Jira: PROJ-123" || items[0].Arg != "https://jira.example.com/browse/PROJ-123" {
+		t.Errorf("the keyword should select the template with the argument %+v", items)
+	}
+
+	items = render("wiki", "go", "language")
+	if len(items) != 1 || items[0].Arg != "https://en.wikipedia.org/w/index.php?search=go%20language" {
+		t.Errorf("the first word should select the template by the title %+v", items)
+	}
+
+	items = render("foo", "bar")
+	if len(items) != 1 || items[0].Title != "Search" || items[0].Valid == nil || *items[0].Valid {
+		t.Errorf("the template which the first word does not select should not be expanded %+v", items)
+	}
+
+	items = render("jira")
+	if len(items) == 0 || items[0].Autocomplete != "jira " || items[0].Valid == nil || *items[0].Valid {
+		t.Errorf("the template without the argument should be completed %+v", items)
+	}
+}
@@ -2,66 +2,57 @@ package cmd
 
 import (
 	"errors"
-	"os"
+	"fmt"
 	"time"
 
 	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
 	"github.com/spf13/viper"
 )
 
-const (
-	firefoxDefaultProfileName = "default"
-	chromeDefaultProfileName  = "default"
-)
-
-var (
-	firefoxDefaultProfilePath = os.ExpandEnv("${HOME}/Library/Application Support/Firefox/Profiles")
-	chromeDefaultProfilePath  = os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome")
-)
-
 // Config configuration which browser bookmark read
 type Config struct {
-	Firefox          Firefox `mapstructure:"firefox"`
-	Chrome           Chrome  `mapstructure:"chrome"`
-	Safari           Safari  `mapstructure:"safari"`
-	RemoveDuplicates bool    `mapstructure:"remove_duplicates"`
-	MaxCacheAge      int     `mapstructure:"cache_age_hours"`
-}
-
-// Firefox Configuration
-type Firefox struct {
-	Enable      bool   `mapstructure:"enable"`
-	ProfileName string `mapstructure:"profile_name,omitempty"`
-	ProfilePath string `mapstructure:"profile_path,omitempty"`
-}
-
-// Chrome Configuration
-type Chrome struct {
-	Enable      bool   `mapstructure:"enable"`
-	ProfileName string `mapstructure:"profile_name,omitempty"`
-	ProfilePath string `mapstructure:"profile_path,omitempty"`
+	// Sources are configurations of registered sources by the name
+	Sources          map[string]bookmarker.SourceConfig `mapstructure:"-"`
+	RemoveDuplicates bool                               `mapstructure:"remove_duplicates"`
+	// Visits attaches visit statistics of browser history to rank bookmarks
+	Visits bool `mapstructure:"visits"`
+	// Favicons shows site icons of browsers instead of browser icons.
+	// It is off by default as favicon databases of browsers are read whenever the cache is rebuilt
+	Favicons bool `mapstructure:"favicons"`
+	// HistoryMaxAgeDays and HistoryMaxEntries cap history of each profile
+	HistoryMaxAgeDays int `mapstructure:"history_max_age_days"`
+	HistoryMaxEntries int `mapstructure:"history_max_entries"`
+	// MixHistory ranks history with bookmarks. By default, history comes after bookmarks
+	MixHistory bool `mapstructure:"mix_history"`
+	// BrowserPriority decides which bookmark wins when duplicates are merged
+	BrowserPriority []string `mapstructure:"browser_priority"`
+	// URLNormalization configures how to compare urls to remove duplicates
+	URLNormalization *bookmarker.URLNormalizer `mapstructure:"url_normalization"`
+	// SearchWeights are weights of scores of titles, domains, urls and folders
+	SearchWeights *bookmarker.SearchWeights `mapstructure:"search_weights"`
+	MaxCacheAge   int                       `mapstructure:"cache_age_hours"`
 }
 
-// Safari Configuration
-type Safari struct {
-	Enable bool `mapstructure:"enable"`
-}
+const (
+	defaultHistoryMaxAgeDays = 30
+	defaultHistoryMaxEntries = 500
+)
 
 // NewConfig return alfred bookmark configuration
 func newConfig() (*Config, error) {
-	c := new(Config)
+	c := &Config{
+		Sources:           make(map[string]bookmarker.SourceConfig),
+		URLNormalization:  bookmarker.NewURLNormalizer(),
+		SearchWeights:     bookmarker.NewSearchWeights(),
+		HistoryMaxAgeDays: defaultHistoryMaxAgeDays,
+		HistoryMaxEntries: defaultHistoryMaxEntries,
+	}
 	viper.SetConfigType("yaml")
 	viper.SetConfigName(".alfred-bookmarks")
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("$HOME/.config/")
 	viper.AddConfigPath("$HOME/")
 
-	// Set default value overwritten with config file
-	viper.SetDefault("firefox.profile_name", firefoxDefaultProfileName)
-	viper.SetDefault("firefox.profile_path", firefoxDefaultProfilePath)
-	viper.SetDefault("chrome.profile_name", chromeDefaultProfileName)
-	viper.SetDefault("chrome.profile_path", chromeDefaultProfilePath)
-	defer c.resolvePath()
 	if err := viper.ReadInConfig(); err != nil {
 		// Try to continue using available bookmarks if config file does not exist
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -74,62 +65,45 @@ func newConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Note: default values of each source are overwritten with the config file
+	for _, s := range bookmarker.Sources() {
+		key := s.Name
+		cfg, err := s.Decode(func(v interface{}) error {
+			return viper.UnmarshalKey(key, v)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s configuration: %w", key, err)
+		}
+		c.Sources[key] = cfg
+	}
+
 	return c, nil
 }
 
 func availableConfig() (*Config, error) {
 	c := &Config{
-		RemoveDuplicates: true,
-	}
-	_, firefoxErr := bookmarker.GetFirefoxBookmarkFile(firefoxDefaultProfilePath, firefoxDefaultProfileName)
-	_, chromeErr := bookmarker.GetChromeBookmarkFile(chromeDefaultProfilePath, chromeDefaultProfileName)
-	_, safariErr := bookmarker.GetSafariBookmarkFile()
-	if firefoxErr != nil && chromeErr != nil && safariErr != nil {
-		return c, errors.New("found no available bookmarks on your computer")
+		Sources:           make(map[string]bookmarker.SourceConfig),
+		RemoveDuplicates:  true,
+		URLNormalization:  bookmarker.NewURLNormalizer(),
+		SearchWeights:     bookmarker.NewSearchWeights(),
+		HistoryMaxAgeDays: defaultHistoryMaxAgeDays,
+		HistoryMaxEntries: defaultHistoryMaxEntries,
 	}
 
-	handlers := []struct {
-		err      error
-		activate func()
-	}{
-		{
-			err: firefoxErr,
-			activate: func() {
-				c.Firefox.Enable = true
-				c.Firefox.ProfileName = firefoxDefaultProfileName
-				c.Firefox.ProfilePath = firefoxDefaultProfilePath
-			},
-		},
-		{
-			err: chromeErr,
-			activate: func() {
-				c.Chrome.Enable = true
-				c.Chrome.ProfileName = chromeDefaultProfileName
-				c.Chrome.ProfilePath = chromeDefaultProfilePath
-			},
-		},
-		{
-			err: safariErr,
-			activate: func() {
-				c.Safari.Enable = true
-			},
-		},
-	}
-	for _, h := range handlers {
-		if err := h.err; err != nil {
+	for _, s := range bookmarker.Sources() {
+		cfg, err := s.Detect()
+		if err != nil {
 			awf.Logger().Infof("unavailable %s\n", err)
 			continue
 		}
-		h.activate()
+		c.Sources[s.Name] = cfg
+	}
+	if len(c.Sources) == 0 {
+		return c, errors.New("found no available bookmarks on your computer")
 	}
 	return c, nil
 }
 
-func (c *Config) resolvePath() {
-	c.Firefox.ProfilePath = os.ExpandEnv(c.Firefox.ProfilePath)
-	c.Chrome.ProfilePath = os.ExpandEnv(c.Chrome.ProfilePath)
-}
-
 func convertDefaultTTL(hour int) time.Duration {
 	if hour == 0 {
 		hour = 24
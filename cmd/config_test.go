@@ -7,25 +7,57 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
 )
 
-// testConfig is the same value as .alfred-bookmarks
-var testConfig = &Config{
-	RemoveDuplicates: true,
-	// disable cache
-	MaxCacheAge: -1,
-	Firefox: Firefox{
-		Enable:      true,
-		ProfileName: "default",
-		ProfilePath: firefoxDefaultProfilePath,
-	},
-	Chrome: Chrome{
-		Enable:      true,
-		ProfileName: "Default",
-		ProfilePath: os.ExpandEnv("${HOME}/Library/mydir/Google/Chrome"),
-	},
+// defaultSourceConfigs returns default configurations of the registered sources
+func defaultSourceConfigs() map[string]bookmarker.SourceConfig {
+	cfgs := make(map[string]bookmarker.SourceConfig)
+	for _, s := range bookmarker.Sources() {
+		cfg, err := s.Decode(func(v interface{}) error { return nil })
+		if err != nil {
+			panic(err)
+		}
+		cfgs[s.Name] = cfg
+	}
+	return cfgs
+}
+
+// testProfileConfig returns an enabled default configuration of the browser
+func testProfileConfig(name string) *bookmarker.ProfileConfig {
+	c := defaultSourceConfigs()[name].(*bookmarker.ProfileConfig)
+	c.Enable = true
+	return c
 }
 
+// testConfig is the same value as .alfred-bookmarks
+var testConfig = func() *Config {
+	sources := defaultSourceConfigs()
+	sources["firefox"].(*bookmarker.ProfileConfig).Enable = true
+	chrome := sources["chrome"].(*bookmarker.ProfileConfig)
+	chrome.Enable = true
+	chrome.ProfileName = "Default"
+	chrome.ProfilePath = os.ExpandEnv("${HOME}/Library/mydir/Google/Chrome")
+	normalizer := bookmarker.NewURLNormalizer()
+	normalizer.IgnoreWWW = false
+	normalizer.TrackingParams = []string{"ref"}
+	weights := bookmarker.NewSearchWeights()
+	weights.URL = 0.9
+	return &Config{
+		Sources:           sources,
+		RemoveDuplicates:  true,
+		Visits:            true,
+		HistoryMaxAgeDays: 7,
+		HistoryMaxEntries: defaultHistoryMaxEntries,
+		BrowserPriority:   []string{"safari", "firefox"},
+		URLNormalization:  normalizer,
+		SearchWeights:     weights,
+		// disable cache
+		MaxCacheAge: -1,
+	}
+}()
+
 func TestNewConfig(t *testing.T) {
 	tests := []struct {
 		description string
@@ -57,19 +89,17 @@ func Test_availableConfig(t *testing.T) {
 		{
 			name: "all available as setup-test-dir.sh prepares directories",
 			want: &Config{
-				RemoveDuplicates: true,
-				Firefox: Firefox{
-					Enable:      true,
-					ProfileName: "default",
-					ProfilePath: firefoxDefaultProfilePath,
-				},
-				Chrome: Chrome{
-					Enable:      true,
-					ProfileName: "default",
-					ProfilePath: chromeDefaultProfilePath,
-				},
-				Safari: Safari{
-					Enable: true,
+				RemoveDuplicates:  true,
+				URLNormalization:  bookmarker.NewURLNormalizer(),
+				SearchWeights:     bookmarker.NewSearchWeights(),
+				HistoryMaxAgeDays: defaultHistoryMaxAgeDays,
+				HistoryMaxEntries: defaultHistoryMaxEntries,
+				Sources: map[string]bookmarker.SourceConfig{
+					"firefox": testProfileConfig("firefox"),
+					"chrome":  testProfileConfig("chrome"),
+					"safari":  &bookmarker.SafariConfig{Enable: true},
+					"brave":   testProfileConfig("brave"),
+					"opera":   testProfileConfig("opera"),
 				},
 			},
 		},
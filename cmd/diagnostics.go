@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+// diagnostics shows which files bookmarks are loaded from and sources which fail
+func diagnostics(cfg *Config, args ...string) {
+	if err := runDiagnostics(cfg, os.Stdout, args...); err != nil {
+		awf.Fatal("failed to diagnose bookmarks", err.Error())
+	}
+}
+
+func runDiagnostics(cfg *Config, out io.Writer, args ...string) error {
+	fs := flag.NewFlagSet("diagnostics", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	manager, err := newManager(cfg)
+	if err != nil {
+		return err
+	}
+	bookmarks, err := manager.Bookmarks()
+	var loadErrs bookmarker.LoadErrors
+	if err != nil && !errors.As(err, &loadErrs) {
+		return err
+	}
+
+	for _, f := range loadedFiles(manager) {
+		fmt.Fprintf(out, "%s: %s\n", sourceName(string(f.BookmarkerName), f.Profile), loadedFileStatus(f))
+	}
+	for _, e := range loadErrs {
+		fmt.Fprintln(out, e.Error())
+	}
+	fmt.Fprintf(out, "%d bookmarks\n", len(bookmarks))
+	return nil
+}
+
+// loadedFiles returns files which bookmarks are loaded from if the bookmarker reports them
+func loadedFiles(b bookmarker.Bookmarker) []*bookmarker.LoadedFile {
+	ls, ok := b.(bookmarker.LoadedFileSource)
+	if !ok {
+		return nil
+	}
+	return ls.LoadedFiles()
+}
+
+// logLoadedFiles logs files which bookmarks are loaded from. fallback files and files with problems are warnings
+func logLoadedFiles(b bookmarker.Bookmarker) {
+	for _, f := range loadedFiles(b) {
+		msg := fmt.Sprintf("%s: %s", sourceName(string(f.BookmarkerName), f.Profile), loadedFileStatus(f))
+		if f.Fallback != nil || f.Warning != nil {
+			awf.Logger().Warnln(msg)
+			continue
+		}
+		awf.Logger().Infoln(msg)
+	}
+}
+
+func loadedFileStatus(f *bookmarker.LoadedFile) string {
+	switch {
+	case f.Fallback != nil:
+		return fmt.Sprintf("loaded %s as a fallback: %s", f.Path, f.Fallback)
+	case f.Warning != nil:
+		return fmt.Sprintf("loaded %s with a warning: %s", f.Path, f.Warning)
+	default:
+		return fmt.Sprintf("loaded %s", f.Path)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+func Test_runDiagnostics(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "pkg", "bookmarker", "testdata", "test-chrome-bookmarks-checksum.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the checksum of the file does not match the bookmarks
+	mismatched, err := os.ReadFile(filepath.Join("..", "pkg", "bookmarker", "testdata", "test-chrome-bookmarks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profilePath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(profilePath, "Default"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	bookmarkPath := filepath.Join(profilePath, "Default", "Bookmarks")
+	chrome := testProfileConfig("chrome")
+	chrome.ProfilePath = profilePath
+	chrome.ProfileName = "Default"
+	cfg := &Config{
+		Sources: map[string]bookmarker.SourceConfig{
+			"chrome": chrome,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		bookmark []byte
+		backup   []byte
+		want     string
+	}{
+		{
+			name:     "bookmark file",
+			bookmark: data,
+			want:     "loaded " + bookmarkPath + "\n",
+		},
+		{
+			name:     "fallback to the backup",
+			bookmark: data[:len(data)/2],
+			backup:   data,
+			want:     "loaded " + bookmarkPath + ".bak as a fallback: failed to decode Bookmarks",
+		},
+		{
+			name:     "checksum mismatch without backups",
+			bookmark: mismatched,
+			want:     "loaded " + bookmarkPath + " with a warning: checksum mismatch of Bookmarks\n",
+		},
+		{
+			name:     "broken files",
+			bookmark: data[:len(data)/2],
+			want:     "failed to load bookmarks in chrome (Default)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(bookmarkPath, tt.bookmark, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			os.Remove(bookmarkPath + ".bak")
+			if tt.backup != nil {
+				if err := os.WriteFile(bookmarkPath+".bak", tt.backup, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			buf := new(bytes.Buffer)
+			if err := runDiagnostics(cfg, buf); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got := buf.String(); !strings.Contains(got, tt.want) {
+				t.Errorf("want: %q\ngot: %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+// export writes bookmarks of enabled sources into a file or stdout
+func export(cfg *Config, args ...string) {
+	if err := runExport(cfg, os.Stdout, args...); err != nil {
+		awf.Fatal("failed to export bookmarks", err.Error())
+	}
+}
+
+func runExport(cfg *Config, out io.Writer, args ...string) error {
+	var format, output string
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&format, "format", string(bookmarker.ExportHTML), "html, json, csv or markdown")
+	fs.StringVarP(&output, "output", "o", "", "output file. stdout if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !isExportFormat(format) {
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	manager, err := newManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, err := manager.Bookmarks()
+	var loadErrs bookmarker.LoadErrors
+	if err != nil && !errors.As(err, &loadErrs) {
+		return err
+	}
+	// export bookmarks of the other sources even if some sources fail
+	for _, e := range loadErrs {
+		awf.Logger().Warnln(e.Error())
+	}
+
+	if output == "" {
+		return bookmarks.Export(out, bookmarker.ExportFormat(format))
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := bookmarks.Export(f, bookmarker.ExportFormat(format)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func isExportFormat(format string) bool {
+	for _, f := range bookmarker.ExportFormats() {
+		if string(f) == format {
+			return true
+		}
+	}
+	return false
+}
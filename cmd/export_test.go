@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+func Test_runExport(t *testing.T) {
+	cfg := &Config{
+		Sources: map[string]bookmarker.SourceConfig{
+			"chrome": testProfileConfig("chrome"),
+			"safari": &bookmarker.SafariConfig{Enable: true},
+		},
+	}
+	output := filepath.Join(t.TempDir(), "bookmarks.csv")
+	tests := []struct {
+		name      string
+		args      []string
+		output    string
+		expectErr bool
+	}{
+		{
+			name: "export csv into stdout",
+			args: []string{"--format", "csv"},
+		},
+		{
+			name:   "export csv into a file",
+			args:   []string{"--format", "csv", "-o", output},
+			output: output,
+		},
+		{
+			name:      "unsupported format",
+			args:      []string{"--format", "xml"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := runExport(cfg, buf, tt.args...)
+			if tt.expectErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			data := buf.Bytes()
+			if tt.output != "" {
+				if data, err = os.ReadFile(tt.output); err != nil {
+					t.Fatal(err)
+				}
+			}
+			records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			// a header, 8 chrome bookmarks and 6 safari bookmarks
+			if len(records) != 15 {
+				t.Errorf("want 15 records, got %d", len(records))
+			}
+		})
+	}
+}
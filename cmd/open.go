@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+// open opens an url with the browser profile which the bookmark comes from
+func open(cfg *Config, args ...string) {
+	cmd, err := parseOpen(cfg, args...)
+	if err != nil {
+		awf.Fatal("failed to open the bookmark", err.Error())
+	}
+
+	if err := cmd.Run(); err != nil {
+		awf.Fatal("failed to open the bookmark", err.Error())
+	}
+}
+
+func parseOpen(cfg *Config, args ...string) (*exec.Cmd, error) {
+	var browser, profile string
+	fs := flag.NewFlagSet("open", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&browser, "browser", "", "browser name")
+	fs.StringVar(&profile, "profile", "", "profile directory name")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("an url is required but got %v", fs.Args())
+	}
+	return openCommand(cfg, browser, profile, fs.Arg(0))
+}
+
+// openCommand returns a command to open the url with the profile of the browser
+func openCommand(cfg *Config, browser, profile, url string) (*exec.Cmd, error) {
+	s, ok := bookmarker.LookupSource(browser)
+	if !ok || s.App == "" {
+		return nil, fmt.Errorf("unsupported browser: %s", browser)
+	}
+
+	if profile == "" {
+		return exec.Command("open", "-a", s.App, url), nil
+	}
+
+	args := []string{"-na", s.App, "--args"}
+	if browser == string(bookmarker.Firefox) || browser == string(bookmarker.FirefoxTabs) {
+		// firefox requires an absolute path of the profile directory. open tabs are opened with firefox as well
+		profilePath := ""
+		if c, ok := cfg.Sources[browser].(*bookmarker.ProfileConfig); ok {
+			profilePath = c.ProfilePath
+		}
+		args = append(args, "-profile", filepath.Join(profilePath, profile))
+	} else {
+		args = append(args, "--profile-directory="+profile)
+	}
+	args = append(args, url)
+	return exec.Command("open", args...), nil
+}
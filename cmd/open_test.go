@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseOpen(t *testing.T) {
+	cfg := &Config{
+		Sources: defaultSourceConfigs(),
+	}
+	firefoxDefaultProfilePath := testProfileConfig("firefox").ProfilePath
+	tests := []struct {
+		name        string
+		args        []string
+		want        []string
+		expectedErr bool
+	}{
+		{
+			name: "chrome profile",
+			args: []string{"--browser", "chrome", "--profile", "Profile 1", "https://example.com/"},
+			want: []string{"open", "-na", "Google Chrome", "--args", "--profile-directory=Profile 1", "https://example.com/"},
+		},
+		{
+			name: "firefox profile",
+			args: []string{"--browser", "firefox", "--profile", "xxxxx.default", "https://example.com/"},
+			want: []string{"open", "-na", "Firefox", "--args", "-profile",
+				filepath.Join(firefoxDefaultProfilePath, "xxxxx.default"), "https://example.com/"},
+		},
+		{
+			name: "firefox tabs profile",
+			args: []string{"--browser", "firefox_tabs", "--profile", "xxxxx.default", "https://example.com/"},
+			want: []string{"open", "-na", "Firefox", "--args", "-profile",
+				filepath.Join(testProfileConfig("firefox_tabs").ProfilePath, "xxxxx.default"), "https://example.com/"},
+		},
+		{
+			name: "no profile",
+			args: []string{"--browser", "safari", "https://example.com/"},
+			want: []string{"open", "-a", "Safari", "https://example.com/"},
+		},
+		{
+			name:        "unsupported browser",
+			args:        []string{"--browser", "unknown", "https://example.com/"},
+			expectedErr: true,
+		},
+		{
+			name:        "no url",
+			args:        []string{"--browser", "chrome"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := parseOpen(cfg, tt.args...)
+			if tt.expectedErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectedErr && err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, cmd.Args); diff != "" {
+				t.Errorf("-want +got\n%+v", diff)
+			}
+		})
+	}
+}
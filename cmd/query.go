@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+// queryFields evaluate a value of a field-qualified term like `domain:github.com` against a bookmark.
+// values are lower case
+var queryFields = map[string]func(b *bookmarker.Bookmark, value string) bool{
+	"domain": func(b *bookmarker.Bookmark, value string) bool {
+		return containsFold(b.Domain, value)
+	},
+	"url": func(b *bookmarker.Bookmark, value string) bool {
+		return containsFold(b.URI, value)
+	},
+	"folder": func(b *bookmarker.Bookmark, value string) bool {
+		if containsFold(b.Folder, value) {
+			return true
+		}
+		for _, o := range b.Origins {
+			if containsFold(o.Folder, value) {
+				return true
+			}
+		}
+		return false
+	},
+	"browser": func(b *bookmarker.Bookmark, value string) bool {
+		if matchBrowser(string(b.BookmarkerName), value) {
+			return true
+		}
+		for _, o := range b.Origins {
+			if matchBrowser(string(o.BookmarkerName), value) {
+				return true
+			}
+		}
+		return false
+	},
+	"is": func(b *bookmarker.Bookmark, value string) bool {
+		// items of reading lists have the status
+		switch value {
+		case "unread":
+			return b.ReadingList && b.Unread
+		case "read":
+			return b.ReadingList && !b.Unread
+		}
+		return false
+	},
+	"tag": func(b *bookmarker.Bookmark, value string) bool {
+		for _, tag := range b.Tags {
+			if strings.ToLower(tag) == value {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// queryTerm is a term of a query
+type queryTerm struct {
+	// field is a name of queryFields. empty if the term is free text
+	field string
+	value string
+	// raw is the value as it is typed
+	raw string
+	// phrase is true if the value is quoted
+	phrase bool
+	// negate is true if the term starts with `-`
+	negate bool
+}
+
+// match returns true if the bookmark satisfies the term regardless of negate
+func (t *queryTerm) match(b *bookmarker.Bookmark) bool {
+	if t.field != "" {
+		return queryFields[t.field](b, t.value)
+	}
+	return containsFold(b.Title, t.value) || containsFold(b.URI, t.value)
+}
+
+// query is a search query which consists of free text, field-qualified terms like `folder:work/infra`,
+// quoted phrases and `-term` exclusions
+type query struct {
+	terms []*queryTerm
+}
+
+// parseQuery parses the search query. terms which have no values are ignored as they are being typed
+func parseQuery(s string) *query {
+	q := new(query)
+	for _, token := range splitQuery(s) {
+		t := new(queryTerm)
+		if token[0] == '-' {
+			t.negate = true
+			token = token[1:]
+		}
+		if name, value, ok := strings.Cut(token, ":"); ok {
+			if _, ok := queryFields[strings.ToLower(name)]; ok {
+				t.field = strings.ToLower(name)
+				token = value
+			}
+		}
+		if strings.HasPrefix(token, `"`) {
+			t.phrase = true
+			token = strings.TrimSuffix(strings.TrimPrefix(token, `"`), `"`)
+		}
+		t.raw = token
+		t.value = strings.ToLower(token)
+		if t.value == "" {
+			continue
+		}
+		q.terms = append(q.terms, t)
+	}
+	return q
+}
+
+// splitQuery splits the query by spaces except for spaces in double quotes
+func splitQuery(s string) []string {
+	var tokens []string
+	var token strings.Builder
+	inQuote := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			token.WriteRune(r)
+		case r == ' ' && !inQuote:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
+
+// match returns true if the bookmark satisfies field-qualified terms, phrases and exclusions.
+// Free text is not evaluated as it is fuzzy-matched with titles
+func (q *query) match(b *bookmarker.Bookmark) bool {
+	for _, t := range q.terms {
+		if t.field == "" && !t.phrase && !t.negate {
+			continue
+		}
+		if t.match(b) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// text returns free text to fuzzy-match with titles
+func (q *query) text() string {
+	words := make([]string, 0, len(q.terms))
+	for _, t := range q.terms {
+		if t.field == "" && !t.phrase && !t.negate {
+			words = append(words, t.value)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// words returns words of free text as they are typed
+func (q *query) words() []string {
+	words := make([]string, 0, len(q.terms))
+	for _, t := range q.terms {
+		if t.field == "" && !t.phrase && !t.negate {
+			words = append(words, t.raw)
+		}
+	}
+	return words
+}
+
+// matchBrowser returns true if the value is the name or a part of the display name of the source
+func matchBrowser(name, value string) bool {
+	if name == value {
+		return true
+	}
+	s, ok := bookmarker.LookupSource(name)
+	return ok && containsFold(s.DisplayName, value)
+}
+
+// containsFold reports whether lower-cased substr is within s regardless of case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), substr)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []*queryTerm
+		text  string
+	}{
+		{
+			name:  "free text",
+			query: "git  hub",
+			want: []*queryTerm{
+				{value: "git", raw: "git"},
+				{value: "hub", raw: "hub"},
+			},
+			text: "git hub",
+		},
+		{
+			name:  "fields, phrases and exclusions",
+			query: `Domain:GitHub.com folder:"work/my infra" -tag:k8s "pull request" -draft unknown:x`,
+			want: []*queryTerm{
+				{field: "domain", value: "github.com", raw: "GitHub.com"},
+				{field: "folder", value: "work/my infra", raw: "work/my infra", phrase: true},
+				{field: "tag", value: "k8s", raw: "k8s", negate: true},
+				{value: "pull request", raw: "pull request", phrase: true},
+				{value: "draft", raw: "draft", negate: true},
+				{value: "unknown:x", raw: "unknown:x"},
+			},
+			text: "unknown:x",
+		},
+		{
+			name:  "terms being typed are ignored",
+			query: `- browser: url:"`,
+			text:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := parseQuery(tt.query)
+			if diff := cmp.Diff(tt.want, q.terms, cmp.AllowUnexported(queryTerm{})); diff != "" {
+				t.Errorf("-want +got\n%s", diff)
+			}
+			if got := q.text(); got != tt.text {
+				t.Errorf("want %q, got %q", tt.text, got)
+			}
+		})
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	b := &bookmarker.Bookmark{
+		BookmarkerName: bookmarker.Chrome,
+		Folder:         "/Bookmarks Bar/Work/Infra",
+		Title:          "Kubernetes Pull Requests",
+		Domain:         "github.com",
+		URI:            "https://github.com/kubernetes/kubernetes/pulls",
+		Tags:           []string{"K8s"},
+		Origins: []*bookmarker.Origin{
+			{BookmarkerName: bookmarker.Chrome, Folder: "/Bookmarks Bar/Work/Infra"},
+			{BookmarkerName: bookmarker.Firefox, Folder: "/Bookmark Menu/dev"},
+		},
+	}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: "", want: true},
+		{query: "domain:github.com folder:work/infra tag:k8s url:/pulls", want: true},
+		{query: "browser:chrome", want: true},
+		{query: "browser:google", want: true},
+		{query: "browser:firefox folder:dev", want: true},
+		{query: "browser:safari", want: false},
+		{query: "domain:gitlab.com", want: false},
+		{query: "-tag:k8s", want: false},
+		{query: `"pull requests"`, want: true},
+		{query: `"requests pull"`, want: false},
+		{query: "-kubernetes", want: false},
+		{query: "-gitlab", want: true},
+		{query: "is:unread", want: false},
+		{query: "-is:unread", want: true},
+		// free text is fuzzy-matched by the workflow
+		{query: "xyz", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := parseQuery(tt.query).match(b); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryMatchReadingList(t *testing.T) {
+	unread := &bookmarker.Bookmark{Title: "The Go Blog", ReadingList: true, Unread: true}
+	read := &bookmarker.Bookmark{Title: "Documentation", ReadingList: true}
+	if q := parseQuery("is:unread"); !q.match(unread) || q.match(read) {
+		t.Errorf("is:unread should match only unread items")
+	}
+	if q := parseQuery("is:read"); q.match(unread) || !q.match(read) {
+		t.Errorf("is:read should match only read items")
+	}
+}
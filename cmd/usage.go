@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	// maxUsageEntries is the number of selections to keep. old ones are removed
+	maxUsageEntries = 1000
+	// usageHalfLife is a period in which a weight of a selection decays by half
+	usageHalfLife = 14 * 24 * time.Hour
+)
+
+// usageEntry is a selection of a bookmark
+type usageEntry struct {
+	Query    string    `json:"query"`
+	URL      string    `json:"url"`
+	OpenedAt time.Time `json:"opened_at"`
+}
+
+// usageStore is a history of selections to rank frequently and recently opened bookmarks first
+type usageStore struct {
+	Entries []*usageEntry `json:"entries"`
+}
+
+// usagePath returns a path of the usage store. It is not a cache as it is not rebuilt
+func usagePath() string {
+	return filepath.Join(awf.GetDataDir(), "usage.json")
+}
+
+// loadUsage returns the usage store. An empty store is returned if it does not exist
+func loadUsage() (*usageStore, error) {
+	u := new(usageStore)
+	data, err := os.ReadFile(usagePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return u, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, fmt.Errorf("failed to load the usage: %w", err)
+	}
+	return u, nil
+}
+
+// add records the selection and removes the oldest ones over the size limit
+func (u *usageStore) add(query, url string, openedAt time.Time) {
+	u.Entries = append(u.Entries, &usageEntry{
+		Query:    normalizeUsageQuery(query),
+		URL:      url,
+		OpenedAt: openedAt,
+	})
+	if n := len(u.Entries) - maxUsageEntries; n > 0 {
+		u.Entries = u.Entries[n:]
+	}
+}
+
+// store saves the usage. The file is replaced atomically as selections may be recorded concurrently
+func (u *usageStore) store() error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	path := usagePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// frecency returns boosts of urls for the query.
+// A selection counts more if it is recent and its query is similar to the query
+func (u *usageStore) frecency(query string, now time.Time) map[string]float64 {
+	query = normalizeUsageQuery(query)
+	boosts := make(map[string]float64)
+	for _, e := range u.Entries {
+		age := now.Sub(e.OpenedAt)
+		if age < 0 {
+			age = 0
+		}
+		decay := math.Pow(0.5, float64(age)/float64(usageHalfLife))
+		boosts[e.URL] += decay * usageQuerySimilarity(query, e.Query)
+	}
+	return boosts
+}
+
+// usageQuerySimilarity returns 1 for the same query, a lower weight for a query which is being typed
+// and a small weight for the others so that frequently opened bookmarks come first regardless of queries
+func usageQuerySimilarity(query, recorded string) float64 {
+	switch {
+	case query == recorded:
+		return 1
+	case query != "" && recorded != "" &&
+		(strings.HasPrefix(recorded, query) || strings.HasPrefix(query, recorded)):
+		return 0.7
+	default:
+		return 0.2
+	}
+}
+
+func normalizeUsageQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// clearUsage removes the usage store
+func clearUsage() error {
+	err := os.Remove(usagePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// record records a selection of a bookmark with the query
+func record(_ *Config, args ...string) {
+	if err := runRecord(time.Now(), args...); err != nil {
+		awf.Fatal("failed to record the selection", err.Error())
+	}
+}
+
+func runRecord(now time.Time, args ...string) error {
+	var query string
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&query, "query", "", "query which the bookmark is selected with")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("an url is required but got %v", fs.Args())
+	}
+
+	u, err := loadUsage()
+	if err != nil {
+		// Note: a broken store is replaced with a new one
+		awf.Logger().Warnln(err.Error())
+		u = new(usageStore)
+	}
+	u.add(query, fs.Arg(0), now)
+	return u.store()
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/konoui/go-alfred"
+
+	"github.com/konoui/alfred-bookmarks/pkg/bookmarker"
+)
+
+func TestUsageStore(t *testing.T) {
+	t.Setenv("alfred_workflow_data", t.TempDir())
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	u, err := loadUsage()
+	if err != nil || len(u.Entries) != 0 {
+		t.Fatalf("want an empty store, got %v %v", u, err)
+	}
+
+	if err := runRecord(now, "--query", "Git Hub", "https://github.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runRecord(now.Add(-usageHalfLife), "--query", "git", "https://gitlab.com/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runRecord(now); err == nil {
+		t.Errorf("expect error happens if an url is not passed")
+	}
+
+	u, err = loadUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.Entries) != 2 || u.Entries[0].Query != "git hub" {
+		t.Fatalf("unexpected entries %+v", u.Entries)
+	}
+
+	boosts := u.frecency("git hub", now)
+	if boosts["https://github.com/"] != 1 {
+		t.Errorf("the same query should have the full weight, got %v", boosts["https://github.com/"])
+	}
+	if want := 0.7 * 0.5; boosts["https://gitlab.com/"] != want {
+		t.Errorf("a query being typed should have a decayed weight %v, got %v", want, boosts["https://gitlab.com/"])
+	}
+	if got := u.frecency("aws", now)["https://github.com/"]; got != 0.2 {
+		t.Errorf("another query should have a small weight, got %v", got)
+	}
+
+	// the oldest selections are removed over the size limit
+	for i := 0; i < maxUsageEntries; i++ {
+		u.add("", "https://example.com/", now)
+	}
+	if len(u.Entries) != maxUsageEntries || u.Entries[0].URL != "https://example.com/" {
+		t.Errorf("want %d entries, got %d", maxUsageEntries, len(u.Entries))
+	}
+
+	if err := clearUsage(); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := loadUsage(); err != nil || len(u.Entries) != 0 {
+		t.Errorf("want an empty store after reset, got %v %v", u, err)
+	}
+}
+
+func TestRunFrecency(t *testing.T) {
+	t.Setenv("alfred_workflow_data", t.TempDir())
+	cfg := &Config{
+		MaxCacheAge: -1,
+		Sources: map[string]bookmarker.SourceConfig{
+			"chrome": testProfileConfig("chrome"),
+		},
+	}
+	firstTitle := func(args ...string) string {
+		t.Helper()
+		outBuf := new(bytes.Buffer)
+		awf = alfred.NewWorkflow(
+			alfred.WithLogWriter(new(bytes.Buffer)),
+			alfred.WithOutWriter(outBuf),
+		)
+		r, err := parse(cfg, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exitCode := awf.RunSimple(r.run); exitCode != 0 {
+			t.Fatalf("unexpected error happens %d", exitCode)
+		}
+
+		out := struct {
+			Items []struct {
+				Title string `json:"title"`
+			} `json:"items"`
+		}{}
+		if err := json.Unmarshal(outBuf.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Items) == 0 {
+			t.Fatalf("no items")
+		}
+		return out.Items[0].Title
+	}
+
+	if got := firstTitle("amazon"); got != "Amazon.com" {
+		t.Fatalf("want Amazon.com, got %s", got)
+	}
+	// an opened bookmark comes first for the query
+	if err := runRecord(time.Now(), "--query", "amazon", "https://aws.amazon.com/?nc1=h_ls"); err != nil {
+		t.Fatal(err)
+	}
+	if got := firstTitle("amazon"); got != "Amazon Web Services" {
+		t.Errorf("want Amazon Web Services, got %s", got)
+	}
+
+	if got := firstTitle("--reset-usage", "amazon"); got != "Amazon.com" {
+		t.Errorf("want Amazon.com after reset, got %s", got)
+	}
+}
@@ -1,7 +1,9 @@
 package bookmarker
 
 import (
+	"fmt"
 	"sort"
+	"time"
 )
 
 // bookmarkerName is a type of supported browser name
@@ -14,29 +16,71 @@ const (
 	Chrome bookmarkerName = "chrome"
 	// Safari is supported
 	Safari bookmarkerName = "safari"
+	// Brave is supported
+	Brave bookmarkerName = "brave"
+	// Edge is supported
+	Edge bookmarkerName = "edge"
+	// Vivaldi is supported
+	Vivaldi bookmarkerName = "vivaldi"
+	// Arc is supported
+	Arc bookmarkerName = "arc"
+	// Opera is supported
+	Opera bookmarkerName = "opera"
+	// Chromium is supported
+	Chromium bookmarkerName = "chromium"
+	// HTML is supported. It is a bookmark file which browsers and bookmark services export
+	HTML bookmarkerName = "html"
 )
 
-func getSupportedBookmarkerNames() []bookmarkerName {
-	names := []bookmarkerName{
-		Firefox,
-		Chrome,
-		Safari,
-	}
-	// sort by name asc for making idempotency result
-	sort.Slice(names, func(i, j int) bool {
-		return names[i] < names[j]
-	})
-
-	return names
-}
-
 // Bookmark abstract each browser bookmark
 type Bookmark struct {
 	BookmarkerName bookmarkerName
+	// Profile is a directory name of the browser profile. empty if the browser has no profile
+	Profile string
+	Folder  string
+	Title   string
+	Domain  string
+	URI     string
+	// ID is a stable identifier of the bookmark in the browser like guid
+	ID string
+	// Added is the time when the bookmark is added. zero value if unknown
+	Added time.Time
+	// Modified is the time when the bookmark is modified last. zero value if unknown
+	Modified    time.Time
+	Tags        []string
+	Keyword     string
+	Description string
+	// Index is a position of the bookmark in the folder
+	Index int
+	// Icon is a path of the site icon in the icon cache. empty unless favicons are attached
+	Icon string
+	// Tab is a position of the open tab. nil unless the entry is an open tab
+	Tab *Tab
+	// ReadingList is true if the entry is an item of the reading list. Description is the preview text of it
+	ReadingList bool
+	// Unread is true if the item of the reading list is not read yet
+	Unread bool
+	// History is true if the entry is a visited url in browser history rather than a bookmark
+	History bool
+	// SearchEngine is true if the entry is a search engine of the browser rather than a bookmark
+	SearchEngine bool
+	// VisitCount is the number of visits in browser history. zero unless visits are attached
+	VisitCount int
+	// LastVisited is the last time when the url was visited
+	LastVisited time.Time
+	// Frecency is a score of frequency and recency of firefox
+	Frecency int
+	// Origins are sources which have the same bookmark including this one.
+	// empty unless duplicate bookmarks are merged
+	Origins []*Origin
+}
+
+// Origin is a source of a merged bookmark
+type Origin struct {
+	BookmarkerName bookmarkerName
+	Profile        string
 	Folder         string
 	Title          string
-	Domain         string
-	URI            string
 }
 
 // Bookmarker is a interface to load each bookmark file
@@ -47,15 +91,65 @@ type Bookmarker interface {
 // Bookmarks a slice of Bookmark struct
 type Bookmarks []*Bookmark
 
-func (b Bookmarks) uniqByURI() Bookmarks {
-	m := make(map[string]bool)
-	uniq := make(Bookmarks, 0, len(b))
+// mergeByURI merges bookmarks which have the same url into one bookmark which records every origin.
+// A bookmark of the browser which comes first in the priority wins. The others follow the order of b.
+// urls are compared with canonical forms if the normalizer is not nil
+func (b Bookmarks) mergeByURI(n *URLNormalizer, priority []bookmarkerName) Bookmarks {
+	rank := func(name bookmarkerName) int {
+		for i, p := range priority {
+			if p == name {
+				return i
+			}
+		}
+		return len(priority)
+	}
+
+	groups := make(map[string]Bookmarks)
+	keys := make([]string, 0, len(b))
 	for _, e := range b {
-		if !m[e.URI] {
-			m[e.URI] = true
-			uniq = append(uniq, e)
+		key := e.URI
+		if n != nil {
+			key = n.Normalize(key)
+		}
+		// Note: every open tab is kept as they are not bookmarks
+		if e.Tab != nil {
+			key = fmt.Sprintf("tab:%s:%d:%d", e.Profile, e.Tab.Window, e.Tab.Index)
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], e)
+	}
+
+	merged := make(Bookmarks, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) == 1 {
+			merged = append(merged, group[0])
+			continue
+		}
+
+		sort.SliceStable(group, func(i, j int) bool {
+			return rank(group[i].BookmarkerName) < rank(group[j].BookmarkerName)
+		})
+		winner := group[0]
+		winner.Origins = make([]*Origin, 0, len(group))
+		for i, e := range group {
+			if i > 0 {
+				winner.addVisits(e)
+				// Note: keep the status of the reading list even if a bookmark of the url wins
+				winner.ReadingList = winner.ReadingList || e.ReadingList
+				winner.Unread = winner.Unread || e.Unread
+			}
+			winner.Origins = append(winner.Origins, &Origin{
+				BookmarkerName: e.BookmarkerName,
+				Profile:        e.Profile,
+				Folder:         e.Folder,
+				Title:          e.Title,
+			})
 		}
+		merged = append(merged, winner)
 	}
 
-	return uniq
+	return merged
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 const testdataPath = "testdata"
@@ -13,37 +14,85 @@ const testdataPath = "testdata"
 // return "" if got is equal to want regardless of sorted or unsorted.
 // format is "+want -got"
 func DiffBookmark(want, got Bookmarks) string {
-	sort.Slice(want, func(i, j int) bool {
-		return want[i].URI < want[j].URI
-	})
-	sort.Slice(got, func(i, j int) bool {
-		return got[i].URI < got[j].URI
-	})
-
+	sortBookmarks(want)
+	sortBookmarks(got)
 	return cmp.Diff(want, got)
 }
 
-func TestBookmarks_UniqByURI(t *testing.T) {
+// sortBookmarks sorts by uri and profile as same uris come from multiple profiles
+func sortBookmarks(b Bookmarks) {
+	sort.Slice(b, func(i, j int) bool {
+		if b[i].URI != b[j].URI {
+			return b[i].URI < b[j].URI
+		}
+		return b[i].Profile < b[j].Profile
+	})
+}
+
+func TestBookmarks_MergeByURI(t *testing.T) {
 	tests := []struct {
 		name string
 		want Bookmarks
 	}{
 		{
 			name: "enable firefox, chrome, safari and remove dupulication. return chrome bookmark",
-			want: testChromeBookmarks,
+			want: withProfile(testProfile, testChromeBookmarks),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			b := getTestAllBookmarks(t)
-			got := b.uniqByURI()
-			if diff := DiffBookmark(got, tt.want); diff != "" {
+			got := b.mergeByURI(nil, nil)
+			sortBookmarks(got)
+			sortBookmarks(tt.want)
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Bookmark{}, "Origins")); diff != "" {
 				t.Errorf("+want/-got: %s", diff)
 			}
 		})
 	}
 }
 
+func TestBookmarks_MergeByURIOrigins(t *testing.T) {
+	b := Bookmarks{
+		{BookmarkerName: Chrome, Profile: "Default", Folder: "/Bookmarks Bar", Title: "x", URI: "https://x.com/"},
+		{BookmarkerName: Firefox, Profile: "default", Folder: "/menu", Title: "X", URI: "https://x.com/"},
+		{BookmarkerName: Safari, Folder: "/Favorites", Title: "y", URI: "https://y.com/"},
+		{BookmarkerName: Safari, Folder: "/BookmarksBar", Title: "x.com", URI: "https://x.com/"},
+	}
+	got := b.mergeByURI(nil, []bookmarkerName{Safari, Firefox})
+	if len(got) != 2 || got[0] != b[3] || got[1] != b[2] {
+		t.Fatalf("unexpected bookmarks %v", got)
+	}
+
+	want := []*Origin{
+		{BookmarkerName: Safari, Folder: "/BookmarksBar", Title: "x.com"},
+		{BookmarkerName: Firefox, Profile: "default", Folder: "/menu", Title: "X"},
+		{BookmarkerName: Chrome, Profile: "Default", Folder: "/Bookmarks Bar", Title: "x"},
+	}
+	if diff := cmp.Diff(want, got[0].Origins); diff != "" {
+		t.Errorf("+want/-got: %s", diff)
+	}
+	// a bookmark which has no duplicates has no origins
+	if got[1].Origins != nil {
+		t.Errorf("unexpected origins %v", got[1].Origins)
+	}
+}
+
+func TestBookmarks_MergeByURIReadingList(t *testing.T) {
+	b := Bookmarks{
+		{BookmarkerName: Chrome, Title: "Go", URI: "https://go.dev/"},
+		{BookmarkerName: ChromeReadingList, Title: "Go", URI: "https://go.dev/", ReadingList: true, Unread: true},
+		{BookmarkerName: Safari, Title: "Go", URI: "https://go.dev/", ReadingList: true},
+	}
+	got := b.mergeByURI(nil, nil)
+	if len(got) != 1 || got[0] != b[0] {
+		t.Fatalf("unexpected bookmarks %v", got)
+	}
+	if !got[0].ReadingList || !got[0].Unread {
+		t.Errorf("the status of the reading list should be kept %+v", got[0])
+	}
+}
+
 func getTestBookmarks(t *testing.T, opts ...Option) Bookmarks {
 	bookmarer, err := New(opts...)
 	if err != nil {
@@ -0,0 +1,140 @@
+package bookmarker
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultTrackingParams are query parameters which are removed by URLNormalizer.
+// A name which ends with `*` matches parameters which have the prefix
+var defaultTrackingParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "msclkid", "yclid",
+	"mc_cid", "mc_eid", "igshid", "_hsenc", "_hsmi",
+}
+
+// URLNormalizer converts urls into canonical forms to find same bookmarks.
+// Scheme and host are always lower case, default ports are removed and IDN is converted into punycode
+type URLNormalizer struct {
+	// IgnoreScheme treats http as https
+	IgnoreScheme bool `mapstructure:"ignore_scheme"`
+	// IgnoreWWW removes `www.` of the host
+	IgnoreWWW bool `mapstructure:"ignore_www"`
+	// IgnoreTrailingSlash removes a trailing slash of the path
+	IgnoreTrailingSlash bool `mapstructure:"ignore_trailing_slash"`
+	// IgnoreFragment removes a fragment like `#top`
+	IgnoreFragment bool `mapstructure:"ignore_fragment"`
+	// SortQuery sorts query parameters
+	SortQuery bool `mapstructure:"sort_query"`
+	// RemoveTrackingParams removes tracking parameters like `utm_source`
+	RemoveTrackingParams bool `mapstructure:"remove_tracking_params"`
+	// TrackingParams are tracking parameters in addition to well-known ones
+	TrackingParams []string `mapstructure:"tracking_params"`
+}
+
+// NewURLNormalizer returns a normalizer which enables all normalizations
+func NewURLNormalizer() *URLNormalizer {
+	return &URLNormalizer{
+		IgnoreScheme:         true,
+		IgnoreWWW:            true,
+		IgnoreTrailingSlash:  true,
+		IgnoreFragment:       true,
+		SortQuery:            true,
+		RemoveTrackingParams: true,
+	}
+}
+
+// Normalize returns a canonical form of the url. rawURL is returned as it is if it is not a valid url
+func (n *URLNormalizer) Normalize(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	// Note: a default port depends on the original scheme
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = n.normalizeHost(u.Scheme, u.Host)
+	if n.IgnoreScheme && u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	if n.IgnoreTrailingSlash {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
+	if n.IgnoreFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+	u.RawQuery = n.normalizeQuery(u.RawQuery)
+	u.ForceQuery = false
+	return u.String()
+}
+
+func (n *URLNormalizer) normalizeHost(scheme, host string) string {
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname, port = host, ""
+	}
+
+	hostname = strings.ToLower(hostname)
+	if ascii, err := idna.Lookup.ToASCII(hostname); err == nil {
+		hostname = ascii
+	}
+	if n.IgnoreWWW {
+		hostname = strings.TrimPrefix(hostname, "www.")
+	}
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+
+	if port == "" {
+		return hostname
+	}
+	return net.JoinHostPort(hostname, port)
+}
+
+// normalizeQuery removes tracking parameters and sorts parameters without decoding them
+func (n *URLNormalizer) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := make([]string, 0, strings.Count(rawQuery, "&")+1)
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(param, "=")
+		if n.RemoveTrackingParams && n.isTrackingParam(key) {
+			continue
+		}
+		params = append(params, param)
+	}
+	if n.SortQuery {
+		sort.Strings(params)
+	}
+	return strings.Join(params, "&")
+}
+
+func (n *URLNormalizer) isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	for _, params := range [][]string{defaultTrackingParams, n.TrackingParams} {
+		for _, p := range params {
+			p = strings.ToLower(p)
+			if strings.HasSuffix(p, "*") && strings.HasPrefix(key, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+			if key == p {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package bookmarker
+
+import (
+	"testing"
+)
+
+func TestURLNormalizer_Normalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalizer *URLNormalizer
+		urls       []string
+		want       string
+	}{
+		{
+			name:       "scheme, trailing slash, www, tracking parameters and fragment",
+			normalizer: NewURLNormalizer(),
+			urls: []string{
+				"http://x.com",
+				"https://x.com/",
+				"https://www.x.com/?utm_source=a",
+				"https://x.com/#top",
+				"HTTPS://X.COM:443/?fbclid=abc&UTM_MEDIUM=b",
+			},
+			want: "https://x.com",
+		},
+		{
+			name:       "query parameter order",
+			normalizer: NewURLNormalizer(),
+			urls: []string{
+				"https://example.com/search?q=go&lang=en",
+				"https://example.com/search/?lang=en&q=go&utm_campaign=c",
+			},
+			want: "https://example.com/search?lang=en&q=go",
+		},
+		{
+			name:       "IDN and default port",
+			normalizer: NewURLNormalizer(),
+			urls: []string{
+				"http://例え.テスト:80/パス",
+				"https://xn--r8jz45g.xn--zckzah/パス",
+			},
+			want: "https://xn--r8jz45g.xn--zckzah/%E3%83%91%E3%82%B9",
+		},
+		{
+			name:       "custom tracking parameters",
+			normalizer: &URLNormalizer{RemoveTrackingParams: true, TrackingParams: []string{"ref", "src_*"}},
+			urls: []string{
+				"https://example.com/a?ref=x&src_id=1&id=2",
+				"https://example.com/a?id=2",
+			},
+			want: "https://example.com/a?id=2",
+		},
+		{
+			name:       "disabled normalizations keep scheme, www, trailing slash, fragment and order",
+			normalizer: &URLNormalizer{},
+			urls: []string{
+				"http://WWW.example.com:80/a/?b=1&a=2#top",
+			},
+			want: "http://www.example.com/a/?b=1&a=2#top",
+		},
+		{
+			name:       "invalid url is not changed",
+			normalizer: NewURLNormalizer(),
+			urls: []string{
+				"javascript:alert(1)",
+			},
+			want: "javascript:alert(1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, u := range tt.urls {
+				if got := tt.normalizer.Normalize(u); got != tt.want {
+					t.Errorf("Normalize(%s) want %s, got %s", u, tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBookmarks_MergeByCanonicalURI(t *testing.T) {
+	b := Bookmarks{
+		{BookmarkerName: Chrome, URI: "https://www.x.com/?utm_source=a"},
+		{BookmarkerName: Firefox, URI: "http://x.com"},
+		{BookmarkerName: Safari, URI: "https://y.com/#top"},
+	}
+	got := b.mergeByURI(NewURLNormalizer(), nil)
+	if len(got) != 2 || got[0] != b[0] || got[1] != b[2] {
+		t.Errorf("unexpected bookmarks %v", got)
+	}
+	// the bookmark keeps the original uri
+	if got[0].URI != "https://www.x.com/?utm_source=a" {
+		t.Errorf("uri is changed %s", got[0].URI)
+	}
+}
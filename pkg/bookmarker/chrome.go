@@ -2,6 +2,7 @@ package bookmarker
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,6 +17,8 @@ type chromeBookmarkEntry struct {
 	URL          string                 `json:"url,omitempty"`
 	Children     []*chromeBookmarkEntry `json:"children,omitempty"`
 	DateModified string                 `json:"date_modified,omitempty"`
+	// index is a position in the parent folder
+	index int
 }
 
 type chromeBookmarkRoot struct {
@@ -28,14 +31,60 @@ type chromeBookmarkRoot struct {
 	Version int `json:"version"`
 }
 
+// ErrChecksumMismatch is returned if the checksum of a chrome bookmark file does not match the bookmarks
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
 type chromeBookmark struct {
+	name         bookmarkerName
 	bookmarkRoot chromeBookmarkRoot
 	bookmarkPath string
+	// loadedPath is the file which bookmarks are loaded from. It is the backup if the bookmark file is broken
+	loadedPath string
+	// fallbackErr is the reason why the backup is loaded
+	fallbackErr error
+	// warning is the checksum mismatch of the bookmark file which is loaded as no valid backup exists
+	warning error
 }
 
 // NewChrome returns a new chrome instance to get bookmarks
 func NewChrome(path string) Bookmarker {
+	return newChromium(Chrome, path)
+}
+
+// NewBrave returns a new brave instance to get bookmarks
+func NewBrave(path string) Bookmarker {
+	return newChromium(Brave, path)
+}
+
+// NewEdge returns a new microsoft edge instance to get bookmarks
+func NewEdge(path string) Bookmarker {
+	return newChromium(Edge, path)
+}
+
+// NewVivaldi returns a new vivaldi instance to get bookmarks
+func NewVivaldi(path string) Bookmarker {
+	return newChromium(Vivaldi, path)
+}
+
+// NewArc returns a new arc instance to get bookmarks
+func NewArc(path string) Bookmarker {
+	return newChromium(Arc, path)
+}
+
+// NewOpera returns a new opera instance to get bookmarks
+func NewOpera(path string) Bookmarker {
+	return newChromium(Opera, path)
+}
+
+// NewChromium returns a new chromium instance to get bookmarks
+func NewChromium(path string) Bookmarker {
+	return newChromium(Chromium, path)
+}
+
+// newChromium returns a bookmarker of chromium-based browsers as they share the bookmark format
+func newChromium(name bookmarkerName, path string) *chromeBookmark {
 	return &chromeBookmark{
+		name:         name,
 		bookmarkPath: path,
 	}
 }
@@ -46,9 +95,9 @@ func (b *chromeBookmark) Bookmarks() (bookmarks Bookmarks, err error) {
 		return
 	}
 
-	barBookmarks := b.bookmarkRoot.Roots.BookmarkBar.convertToBookmarks("/")
-	syncedBookmarks := b.bookmarkRoot.Roots.Synced.convertToBookmarks("/")
-	othersBookmarks := b.bookmarkRoot.Roots.Other.convertToBookmarks("/")
+	barBookmarks := b.bookmarkRoot.Roots.BookmarkBar.convertToBookmarks(b.name, "/")
+	syncedBookmarks := b.bookmarkRoot.Roots.Synced.convertToBookmarks(b.name, "/")
+	othersBookmarks := b.bookmarkRoot.Roots.Other.convertToBookmarks(b.name, "/")
 	bookmarks = append(bookmarks, barBookmarks...)
 	bookmarks = append(bookmarks, syncedBookmarks...)
 	bookmarks = append(bookmarks, othersBookmarks...)
@@ -56,19 +105,114 @@ func (b *chromeBookmark) Bookmarks() (bookmarks Bookmarks, err error) {
 	return bookmarks, nil
 }
 
-// load a chrome bookmark file
+// load a chrome bookmark file. Bookmarks.bak is loaded instead if the file is broken or the checksum does not match.
+// If the backup is missing or invalid, the file whose checksum does not match is loaded with the warning
 func (b *chromeBookmark) load() error {
-	f, err := os.Open(b.bookmarkPath)
-	if err != nil {
+	root, err := loadChromeBookmarkFile(b.bookmarkPath)
+	if err == nil {
+		b.bookmarkRoot, b.loadedPath, b.fallbackErr, b.warning = *root, b.bookmarkPath, nil, nil
+		return nil
+	}
+
+	backupPath := b.bookmarkPath + chromeBackupSuffix
+	backup, backupErr := loadChromeBookmarkFile(backupPath)
+	if backupErr == nil {
+		b.bookmarkRoot, b.loadedPath, b.fallbackErr, b.warning = *backup, backupPath, err, nil
+		return nil
+	}
+	if errors.Is(err, ErrChecksumMismatch) {
+		b.bookmarkRoot, b.loadedPath, b.fallbackErr, b.warning = *root, b.bookmarkPath, nil, err
+		return nil
+	}
+	if os.IsNotExist(backupErr) {
 		return err
 	}
+	return fmt.Errorf("%w (backup: %s)", err, backupErr)
+}
+
+// loadChromeBookmarkFile decodes the bookmark file and verifies the checksum.
+// The decoded bookmarks are returned with ErrChecksumMismatch if the checksum does not match.
+// Note: files which have no checksum are not verified like chrome
+func loadChromeBookmarkFile(path string) (*chromeBookmarkRoot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
 	defer f.Close()
 
-	return json.NewDecoder(f).Decode(&b.bookmarkRoot)
+	root := new(chromeBookmarkRoot)
+	if err := json.NewDecoder(f).Decode(root); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filepath.Base(path), err)
+	}
+	if root.Checksum != "" && root.Checksum != root.checksum() {
+		return root, fmt.Errorf("%w of %s", ErrChecksumMismatch, filepath.Base(path))
+	}
+	return root, nil
+}
+
+// LoadedFiles returns the file which bookmarks are loaded from by the last call of Bookmarks
+func (b *chromeBookmark) LoadedFiles() []*LoadedFile {
+	if b.loadedPath == "" {
+		return nil
+	}
+	return []*LoadedFile{{Path: b.loadedPath, Fallback: b.fallbackErr, Warning: b.warning}}
+}
+
+// Files returns the bookmark file
+func (b *chromeBookmark) Files() []string {
+	return []string{b.bookmarkPath}
+}
+
+// historyPath returns the History database next to the bookmark file
+func (b *chromeBookmark) historyPath() string {
+	return filepath.Join(filepath.Dir(b.bookmarkPath), "History")
+}
+
+// visitFiles returns the History database and the write-ahead log
+func (b *chromeBookmark) visitFiles() []string {
+	return []string{b.historyPath(), b.historyPath() + "-wal"}
+}
+
+// Visits returns visit statistics of urls in the History database next to the bookmark file
+func (b *chromeBookmark) Visits() (map[string]*Visit, error) {
+	db, closeDB, err := openSQLiteSnapshot(b.historyPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	const query = `
+SELECT url, visit_count, last_visit_time
+FROM urls
+WHERE visit_count > 0`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query visits: %w", err)
+	}
+	defer rows.Close()
+
+	visits := make(map[string]*Visit)
+	for rows.Next() {
+		var url, lastVisitTime string
+		v := new(Visit)
+		if err := rows.Scan(&url, &v.Count, &lastVisitTime); err != nil {
+			return nil, fmt.Errorf("failed to scan visits: %w", err)
+		}
+		v.LastVisited = convertChromeTime(lastVisitTime)
+		visits[url] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read visits: %w", err)
+	}
+	return visits, nil
 }
 
 // convertToBookmarks parse a entry and children of the entry
-func (entry *chromeBookmarkEntry) convertToBookmarks(folder string) (bookmarks Bookmarks) {
+func (entry *chromeBookmarkEntry) convertToBookmarks(name bookmarkerName, folder string) (bookmarks Bookmarks) {
+	if entry == nil {
+		return
+	}
+
 	switch entry.Type {
 	case "folder":
 		if entry.Children == nil {
@@ -79,8 +223,9 @@ func (entry *chromeBookmarkEntry) convertToBookmarks(folder string) (bookmarks B
 			// we append folder name to parent folder name
 			folder = filepath.Join(folder, entry.Name)
 		}
-		for _, e := range entry.Children {
-			bookmarks = append(bookmarks, e.convertToBookmarks(folder)...)
+		for i, e := range entry.Children {
+			e.index = i
+			bookmarks = append(bookmarks, e.convertToBookmarks(name, folder)...)
 		}
 	case "url":
 		u, err := parseURL(entry.URL)
@@ -89,11 +234,15 @@ func (entry *chromeBookmarkEntry) convertToBookmarks(folder string) (bookmarks B
 		}
 
 		b := &Bookmark{
-			BookmarkerName: Chrome,
+			BookmarkerName: name,
 			Folder:         folder,
 			Title:          entry.Name,
 			URI:            entry.URL,
 			Domain:         u.Host,
+			ID:             entry.GUID,
+			Added:          convertChromeTime(entry.DateAdded),
+			Modified:       convertChromeTime(entry.DateModified),
+			Index:          entry.index,
 		}
 		bookmarks = append(bookmarks, b)
 	}
@@ -103,18 +252,42 @@ func (entry *chromeBookmarkEntry) convertToBookmarks(folder string) (bookmarks B
 
 // GetChromeBookmarkFile returns a chrome bookmark filepath
 // e.g.) GetChromeBookmarkFile(
-//	os.ExpandEnv("${HOME}/Library/Application Support/Firefox/Profiles"),
+//	os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome"),
 //	"default")
 func GetChromeBookmarkFile(profilePath, profileName string) (string, error) {
-	profileDirName, err := searchSuffixDir(profilePath, profileName)
-	if err != nil {
-		return "", err
+	return GetChromiumBookmarkFile(Chrome, profilePath, profileName)
+}
+
+// GetChromiumBookmarkFile returns a bookmark filepath of chromium-based browsers.
+// If profileName is empty, the bookmark file is expected to be directly under profilePath like Opera.
+// e.g.) GetChromiumBookmarkFile(
+//	Brave,
+//	os.ExpandEnv("${HOME}/Library/Application Support/BraveSoftware/Brave-Browser"),
+//	"default")
+func GetChromiumBookmarkFile(name bookmarkerName, profilePath, profileName string) (string, error) {
+	profileDir := profilePath
+	if profileName != "" {
+		profileDirName, err := searchProfileDir(profilePath, profileName, ChromiumProfileList)
+		if err != nil {
+			return "", err
+		}
+		profileDir = filepath.Join(profilePath, profileDirName)
 	}
 
-	bookmarkFile := filepath.Join(profilePath, profileDirName, "Bookmarks")
+	bookmarkFile := filepath.Join(profileDir, "Bookmarks")
 	if err := hasReadCapability(bookmarkFile); err != nil {
-		return "", fmt.Errorf("chrome error: %w", err)
+		return "", fmt.Errorf("%s error: %w", name, err)
 	}
 
 	return bookmarkFile, nil
 }
+
+// ChromiumProfiles returns directory names of chrome or chromium-based browser profiles which have bookmarks.
+// An empty name is included if profilePath has bookmarks directly like Opera
+func ChromiumProfiles(profilePath string) ([]string, error) {
+	names, err := searchProfileDirs(profilePath, "Bookmarks")
+	if _, statErr := os.Stat(filepath.Join(profilePath, "Bookmarks")); statErr == nil {
+		return append([]string{""}, names...), nil
+	}
+	return names, err
+}
@@ -0,0 +1,203 @@
+package bookmarker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ChromeReadingList is a name of the reading list of chrome
+const ChromeReadingList bookmarkerName = "chrome_reading_list"
+
+// chromeReadingListPrefix is a key prefix of reading list entries in the sync database
+const chromeReadingListPrefix = "reading_list-dt-"
+
+// field numbers of ReadingListSpecifics in chromium components/sync/protocol/reading_list_specifics.proto
+const (
+	readingListTitleField        protowire.Number = 2
+	readingListURLField          protowire.Number = 3
+	readingListCreationTimeField protowire.Number = 4
+	readingListStatusField       protowire.Number = 6
+)
+
+// readingListStatusRead is a status of read entries. the others are unread or unseen
+const readingListStatusRead = 1
+
+// chromeReadingListBookmark reads the reading list from the sync database of chrome
+type chromeReadingListBookmark struct {
+	name   bookmarkerName
+	dbPath string
+}
+
+// NewChromeReadingList returns a bookmarker of the reading list in `Sync Data/LevelDB` of the chrome profile
+func NewChromeReadingList(path string) Bookmarker {
+	return &chromeReadingListBookmark{
+		name:   ChromeReadingList,
+		dbPath: path,
+	}
+}
+
+// Bookmarks returns items of the reading list in the reading list folder
+func (b *chromeReadingListBookmark) Bookmarks() (Bookmarks, error) {
+	db, closeDB, err := openLevelDBSnapshot(b.dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	var bookmarks Bookmarks
+	iter := db.NewIterator(util.BytesPrefix([]byte(chromeReadingListPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		bookmark, err := parseReadingListSpecifics(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the reading list: %w", err)
+		}
+		u, err := parseURL(bookmark.URI)
+		if err != nil {
+			continue
+		}
+		bookmark.BookmarkerName = b.name
+		bookmark.Folder = ReadingListFolder
+		bookmark.Domain = u.Host
+		bookmark.ID = string(iter.Key()[len(chromeReadingListPrefix):])
+		bookmark.Index = len(bookmarks)
+		if bookmark.Title == "" {
+			bookmark.Title = bookmark.URI
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read the reading list: %w", err)
+	}
+	return bookmarks, nil
+}
+
+// Files returns files of the database. Note: leveldb appends entries to the log files and rotates them
+func (b *chromeReadingListBookmark) Files() []string {
+	files := []string{b.dbPath}
+	entries, err := os.ReadDir(b.dbPath)
+	if err != nil {
+		return files
+	}
+	for _, e := range entries {
+		if e.Type().IsRegular() && e.Name() != "LOCK" {
+			files = append(files, filepath.Join(b.dbPath, e.Name()))
+		}
+	}
+	return files
+}
+
+// parseReadingListSpecifics decodes a ReadingListSpecifics message into a bookmark
+func parseReadingListSpecifics(data []byte) (*Bookmark, error) {
+	b := &Bookmark{
+		ReadingList: true,
+		Unread:      true,
+	}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		switch {
+		case typ == protowire.BytesType && (num == readingListTitleField || num == readingListURLField):
+			v, n := protowire.ConsumeString(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			if num == readingListTitleField {
+				b.Title = v
+			} else {
+				b.URI = v
+			}
+			data = data[n:]
+		case typ == protowire.VarintType && (num == readingListCreationTimeField || num == readingListStatusField):
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			if num == readingListCreationTimeField {
+				// Note: the reading list counts microseconds from the unix epoch unlike bookmarks
+				b.Added = time.UnixMicro(int64(v)).UTC()
+			} else {
+				b.Unread = v != readingListStatusRead
+			}
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+		}
+	}
+	return b, nil
+}
+
+// openLevelDBSnapshot copies a leveldb directory into a temporary directory and opens the copy read-only.
+// The browser holds the lock of the database while running.
+// The returned close function closes the database and removes the copy
+func openLevelDBSnapshot(path string) (db *leveldb.DB, closeFn func(), err error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	dir, err := os.MkdirTemp("", "alfred-bookmarks-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() || e.Name() == "LOCK" {
+			continue
+		}
+		if err := copyFile(filepath.Join(path, e.Name()), filepath.Join(dir, e.Name())); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+
+	db, err = leveldb.OpenFile(dir, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+
+	closeFn = func() {
+		db.Close()
+		cleanup()
+	}
+	return db, closeFn, nil
+}
+
+// WithChromeReadingList if called, search the reading list of chrome profiles
+func WithChromeReadingList(profilePath string, profileNames ...string) Option {
+	return func(m *Manager) error {
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, ChromiumProfiles, ChromiumProfileList)
+		if err != nil {
+			m.addError(ChromeReadingList, "", err)
+			return nil
+		}
+
+		for _, profileDirName := range profileDirNames {
+			path := filepath.Join(profilePath, profileDirName, "Sync Data", "LevelDB")
+			if err := hasReadCapability(filepath.Join(path, "CURRENT")); err != nil {
+				m.addError(ChromeReadingList, profileDirName, fmt.Errorf("%s error: %w", ChromeReadingList, err))
+				continue
+			}
+			m.add(ChromeReadingList, profileDirName, NewChromeReadingList(path))
+		}
+		return nil
+	}
+}
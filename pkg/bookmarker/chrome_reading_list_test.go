@@ -0,0 +1,87 @@
+package bookmarker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/syndtr/goleveldb/leveldb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestChromeReadingListBookmarks(t *testing.T) {
+	added := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	specifics := func(title, url string, status uint64) []byte {
+		var b []byte
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, url)
+		b = protowire.AppendTag(b, readingListTitleField, protowire.BytesType)
+		b = protowire.AppendString(b, title)
+		b = protowire.AppendTag(b, readingListURLField, protowire.BytesType)
+		b = protowire.AppendString(b, url)
+		b = protowire.AppendTag(b, readingListCreationTimeField, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(added.UnixMicro()))
+		b = protowire.AppendTag(b, readingListStatusField, protowire.VarintType)
+		b = protowire.AppendVarint(b, status)
+		// estimated_read_time_seconds is unknown for us
+		b = protowire.AppendTag(b, 9, protowire.VarintType)
+		b = protowire.AppendVarint(b, 60)
+		return b
+	}
+
+	path := filepath.Join(t.TempDir(), "Sync Data", "LevelDB")
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string][]byte{
+		"reading_list-dt-https://go.dev/blog/": specifics("The Go Blog", "https://go.dev/blog/", 0),
+		"reading_list-dt-https://go.dev/doc/":  specifics("", "https://go.dev/doc/", readingListStatusRead),
+		"reading_list-md-https://go.dev/doc/":  []byte("metadata"),
+		"bookmarks-dt-1":                       []byte("bookmark"),
+	}
+	for k, v := range entries {
+		if err := db.Put([]byte(k), v, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Note: the database is locked while the browser is running
+	defer db.Close()
+
+	got, err := NewChromeReadingList(path).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Bookmarks{
+		{
+			BookmarkerName: ChromeReadingList,
+			Folder:         ReadingListFolder,
+			Title:          "The Go Blog",
+			Domain:         "go.dev",
+			URI:            "https://go.dev/blog/",
+			ID:             "https://go.dev/blog/",
+			Added:          added,
+			ReadingList:    true,
+			Unread:         true,
+		},
+		{
+			BookmarkerName: ChromeReadingList,
+			Folder:         ReadingListFolder,
+			Title:          "https://go.dev/doc/",
+			Domain:         "go.dev",
+			URI:            "https://go.dev/doc/",
+			ID:             "https://go.dev/doc/",
+			Index:          1,
+			Added:          added,
+			ReadingList:    true,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+
+	if _, err := NewChromeReadingList(filepath.Join(t.TempDir(), "LevelDB")).Bookmarks(); err == nil {
+		t.Errorf("expect error happens if the database does not exist")
+	}
+}
@@ -0,0 +1,113 @@
+package bookmarker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ChromeSearchEngines is a name of custom search engines of chrome
+const ChromeSearchEngines bookmarkerName = "chrome_search_engines"
+
+// SearchEnginesFolder is a folder of search engines
+const SearchEnginesFolder = "/Search Engines"
+
+// chromeSearchTerms is a placeholder of urls of search engines
+const chromeSearchTerms = "{searchTerms}"
+
+// chromeSearchEnginesBookmark reads search engines from `Web Data` of chrome as url templates
+type chromeSearchEnginesBookmark struct {
+	name bookmarkerName
+	path string
+}
+
+// NewChromeSearchEngines returns a bookmarker of search engines in `Web Data` of the chrome profile
+func NewChromeSearchEngines(path string) Bookmarker {
+	return &chromeSearchEnginesBookmark{
+		name: ChromeSearchEngines,
+		path: path,
+	}
+}
+
+// Bookmarks returns search engines as templates whose keywords are the keywords of the engines.
+// Only engines which users add or edit are returned. Prepopulated engines and engines which chrome generates
+// from visited sites are replaceable by chrome, so they are skipped.
+// Engines which have placeholders of chrome like {google:baseURL} are not expandable, so they are also skipped
+func (b *chromeSearchEnginesBookmark) Bookmarks() (Bookmarks, error) {
+	db, closeDB, err := openSQLiteSnapshot(b.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	const query = `
+SELECT id, short_name, keyword, url, IFNULL(date_created, 0)
+FROM keywords
+WHERE url LIKE '%{searchTerms}%' AND IFNULL(safe_for_autoreplace, 0) = 0
+ORDER BY id`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search engines: %w", err)
+	}
+	defer rows.Close()
+
+	var bookmarks Bookmarks
+	for rows.Next() {
+		var id, dateCreated int64
+		var title, keyword, uri string
+		if err := rows.Scan(&id, &title, &keyword, &uri, &dateCreated); err != nil {
+			return nil, fmt.Errorf("failed to scan search engines: %w", err)
+		}
+		uri = strings.ReplaceAll(uri, chromeSearchTerms, "%s")
+		if strings.Contains(uri, "{") {
+			continue
+		}
+		u, err := parseURL(uri)
+		if err != nil {
+			continue
+		}
+		bookmarks = append(bookmarks, &Bookmark{
+			BookmarkerName: b.name,
+			Folder:         SearchEnginesFolder,
+			Title:          title,
+			Domain:         u.Host,
+			URI:            uri,
+			ID:             strconv.FormatInt(id, 10),
+			Added:          convertChromeTime(strconv.FormatInt(dateCreated, 10)),
+			Keyword:        keyword,
+			Index:          len(bookmarks),
+			SearchEngine:   true,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search engines: %w", err)
+	}
+	return bookmarks, nil
+}
+
+// Files returns the database and the journal
+func (b *chromeSearchEnginesBookmark) Files() []string {
+	return []string{b.path, b.path + "-journal"}
+}
+
+// WithChromeSearchEngines if called, search custom search engines of chrome profiles
+func WithChromeSearchEngines(profilePath string, profileNames ...string) Option {
+	return func(m *Manager) error {
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, ChromiumProfiles, ChromiumProfileList)
+		if err != nil {
+			m.addError(ChromeSearchEngines, "", err)
+			return nil
+		}
+
+		for _, profileDirName := range profileDirNames {
+			path := filepath.Join(profilePath, profileDirName, "Web Data")
+			if err := hasReadCapability(path); err != nil {
+				m.addError(ChromeSearchEngines, profileDirName, fmt.Errorf("%s error: %w", ChromeSearchEngines, err))
+				continue
+			}
+			m.add(ChromeSearchEngines, profileDirName, NewChromeSearchEngines(path))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package bookmarker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testChromeWebDataSQLFile = filepath.Join(testdataPath, "test-chrome-web-data.sql")
+
+// createTestChromeWebData creates `Web Data` of the sql fixture which has the keywords table of chrome
+func createTestChromeWebData(t *testing.T) string {
+	t.Helper()
+	schema, err := os.ReadFile(testChromeWebDataSQLFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "Web Data")
+	if _, err := openTestDB(t, path).Exec(string(schema)); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestChromeSearchEnginesBookmarks(t *testing.T) {
+	created := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	got, err := NewChromeSearchEngines(createTestChromeWebData(t)).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Note: prepopulated, generated and unexpandable engines are skipped
+	want := Bookmarks{
+		{
+			BookmarkerName: ChromeSearchEngines,
+			Folder:         SearchEnginesFolder,
+			Title:          "Jira",
+			Domain:         "jira.example.com",
+			URI:            "https://jira.example.com/browse/%s",
+			ID:             "5",
+			Added:          created,
+			Keyword:        "jira",
+			SearchEngine:   true,
+		},
+		{
+			BookmarkerName: ChromeSearchEngines,
+			Folder:         SearchEnginesFolder,
+			Title:          "Bing",
+			Domain:         "www.bing.com",
+			URI:            "https://www.bing.com/search?q=%s",
+			ID:             "7",
+			Keyword:        "b",
+			Index:          1,
+			SearchEngine:   true,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+	if got := got[0].Expand("PROJ-123"); got != "https://jira.example.com/browse/PROJ-123" {
+		t.Errorf("unexpected url %s", got)
+	}
+}
+
+func TestWithChromeSearchEngines(t *testing.T) {
+	profilePath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(profilePath, "Default"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(createTestChromeWebData(t), filepath.Join(profilePath, "Default", "Web Data")); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := New(WithChromeSearchEngines(profilePath, "Default"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookmarks, err := m.Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bookmarks {
+		if b.BookmarkerName != ChromeSearchEngines || b.Profile != "Default" || !b.IsTemplate() {
+			t.Errorf("unexpected search engine %+v", b)
+		}
+	}
+	if len(bookmarks) != 2 {
+		t.Errorf("want 2 search engines, got %d", len(bookmarks))
+	}
+}
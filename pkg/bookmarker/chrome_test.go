@@ -1,13 +1,26 @@
 package bookmarker
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 var testChromeBookmarkJSONFile = filepath.Join(testdataPath, "test-chrome-bookmarks.json")
 var defaultChromeProfilePath = os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome")
+var defaultBraveProfilePath = os.ExpandEnv("${HOME}/Library/Application Support/BraveSoftware/Brave-Browser")
+var defaultOperaProfilePath = os.ExpandEnv("${HOME}/Library/Application Support/com.operasoftware.Opera")
+
+// testChromeChecksumJSONFile has a checksum computed by another implementation of the algorithm of chromium
+var testChromeChecksumJSONFile = filepath.Join(testdataPath, "test-chrome-bookmarks-checksum.json")
+
+// testChromeBookmarkChecksum is the checksum of bookmarks of testChromeBookmarkJSONFile.
+// Note: the checksum which the file has does not match the bookmarks
+const testChromeBookmarkChecksum = "f23b7ccfa135e6acdd795aabca019464"
+
 var testChromeBookmarks = Bookmarks{
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -15,6 +28,9 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Google",
 		Domain:         "www.google.com",
 		URI:            "https://www.google.com/",
+		ID:             "065efd70-9e6d-4048-b931-8c9d64af196a",
+		Added:          time.Date(2019, 12, 9, 14, 8, 23, 62568000, time.UTC),
+		Index:          2,
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -22,6 +38,8 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "GitHub",
 		Domain:         "github.com",
 		URI:            "https://github.com/",
+		ID:             "a569a5a3-71a6-427f-8cf9-558729e62f82",
+		Added:          time.Date(2019, 12, 4, 13, 39, 13, 553409000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -29,6 +47,8 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Stack Overflow",
 		Domain:         "stackoverflow.com",
 		URI:            "https://stackoverflow.com/",
+		ID:             "33b15783-7cea-461c-bd59-bbebffa9c0a7",
+		Added:          time.Date(2019, 12, 9, 14, 5, 44, 696167000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -36,6 +56,9 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Amazon Web Services",
 		Domain:         "aws.amazon.com",
 		URI:            "https://aws.amazon.com/?nc1=h_ls",
+		ID:             "0b62d918-3f97-4971-ab4c-ebcffb152a42",
+		Added:          time.Date(2019, 12, 9, 14, 7, 2, 835227000, time.UTC),
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -43,6 +66,8 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Yahoo",
 		Domain:         "www.yahoo.com",
 		URI:            "https://www.yahoo.com/",
+		ID:             "6868e071-77c1-4c1c-9f68-dcff44b709f2",
+		Added:          time.Date(2019, 12, 9, 14, 3, 14, 117251000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -50,6 +75,8 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Facebook",
 		Domain:         "www.facebook.com",
 		URI:            "https://www.facebook.com/",
+		ID:             "a2e1bd18-80df-48c1-b7f5-f050d42d0495",
+		Added:          time.Date(2019, 12, 9, 14, 3, 58, 780213000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -57,6 +84,9 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Twitter",
 		Domain:         "twitter.com",
 		URI:            "https://twitter.com/login",
+		ID:             "1d7752b1-e0cd-42f4-85f8-493000b661d4",
+		Added:          time.Date(2019, 12, 9, 14, 4, 40, 115111000, time.UTC),
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Chrome,
@@ -64,6 +94,8 @@ var testChromeBookmarks = Bookmarks{
 		Title:          "Amazon.com",
 		Domain:         "www.amazon.com",
 		URI:            "https://www.amazon.com/",
+		ID:             "f9ce7434-74cb-4478-a9e0-1b3daa1379ca",
+		Added:          time.Date(2019, 11, 27, 13, 13, 6, 972142000, time.UTC),
 	},
 }
 
@@ -88,6 +120,7 @@ func TestChromeBookmarks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
 			b := chromeBookmark{
+				name:         Chrome,
 				bookmarkPath: tt.bookmarkPath,
 			}
 
@@ -106,3 +139,219 @@ func TestChromeBookmarks(t *testing.T) {
 		})
 	}
 }
+
+func TestChromiumBookmarks(t *testing.T) {
+	tests := []struct {
+		description string
+		bookmarker  Bookmarker
+		want        Bookmarks
+	}{
+		{
+			description: "brave",
+			bookmarker:  NewBrave(testChromeBookmarkJSONFile),
+			want:        testChromiumBookmarks(Brave),
+		},
+		{
+			description: "edge",
+			bookmarker:  NewEdge(testChromeBookmarkJSONFile),
+			want:        testChromiumBookmarks(Edge),
+		},
+		{
+			description: "vivaldi",
+			bookmarker:  NewVivaldi(testChromeBookmarkJSONFile),
+			want:        testChromiumBookmarks(Vivaldi),
+		},
+		{
+			description: "arc",
+			bookmarker:  NewArc(testChromeBookmarkJSONFile),
+			want:        testChromiumBookmarks(Arc),
+		},
+		{
+			description: "opera",
+			bookmarker:  NewOpera(testChromeBookmarkJSONFile),
+			want:        testChromiumBookmarks(Opera),
+		},
+		{
+			description: "chromium",
+			bookmarker:  NewChromium(testChromeBookmarkJSONFile),
+			want:        testChromiumBookmarks(Chromium),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			bookmarks, err := tt.bookmarker.Bookmarks()
+			if err != nil {
+				t.Fatalf("unexpected error got: %+v", err)
+			}
+
+			diff := DiffBookmark(bookmarks, tt.want)
+			if diff != "" {
+				t.Errorf("+want -got\n%+v", diff)
+			}
+		})
+	}
+}
+
+func TestLoadChromeBookmarkFile(t *testing.T) {
+	data, err := os.ReadFile(testChromeChecksumJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		description string
+		bookmark    []byte
+		wantErr     error
+	}{
+		{
+			description: "checksum of non-ascii titles and nested folders",
+			bookmark:    data,
+		},
+		{
+			description: "title is changed",
+			bookmark:    []byte(strings.Replace(string(data), `"name": "Go 言語"`, `"name": "Go"`, 1)),
+			wantErr:     ErrChecksumMismatch,
+		},
+		{
+			description: "url is changed",
+			bookmark:    []byte(strings.Replace(string(data), `"url": "https://go.dev/"`, `"url": "https://go.dev/doc/"`, 1)),
+			wantErr:     ErrChecksumMismatch,
+		},
+		{
+			description: "id is changed",
+			bookmark:    []byte(strings.Replace(string(data), `"id": "7"`, `"id": "70"`, 1)),
+			wantErr:     ErrChecksumMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Bookmarks")
+			writeTestFile(t, path, string(tt.bookmark))
+
+			root, err := loadChromeBookmarkFile(path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if root == nil || root.Roots.BookmarkBar == nil {
+				t.Errorf("bookmarks should be decoded %+v", root)
+			}
+		})
+	}
+}
+
+func TestChromeBookmarksFallback(t *testing.T) {
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := []byte(strings.Replace(string(data), "091f78e180d779522489bba763974d29", testChromeBookmarkChecksum, 1))
+	mismatched := []byte(strings.Replace(string(valid), `"name": "Google"`, `"name": "Goooogle"`, 1))
+	tests := []struct {
+		description string
+		bookmark    []byte
+		backup      []byte
+		wantBackup  bool
+		wantErr     error
+		wantWarning error
+		expectErr   bool
+	}{
+		{
+			description: "valid checksum",
+			bookmark:    valid,
+		},
+		{
+			description: "checksum mismatch falls back to the backup",
+			bookmark:    mismatched,
+			backup:      valid,
+			wantBackup:  true,
+			wantErr:     ErrChecksumMismatch,
+		},
+		{
+			description: "half-written file falls back to the backup",
+			bookmark:    valid[:len(valid)/2],
+			backup:      valid,
+			wantBackup:  true,
+		},
+		{
+			description: "checksum mismatch without backups is a warning",
+			bookmark:    data,
+			wantWarning: ErrChecksumMismatch,
+		},
+		{
+			description: "checksum mismatch with a broken backup is a warning",
+			bookmark:    data,
+			backup:      valid[:len(valid)/2],
+			wantWarning: ErrChecksumMismatch,
+		},
+		{
+			description: "no backup",
+			bookmark:    valid[:len(valid)/2],
+			expectErr:   true,
+		},
+		{
+			description: "broken backup",
+			bookmark:    valid[:len(valid)/2],
+			backup:      mismatched,
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Bookmarks")
+			if err := os.WriteFile(path, tt.bookmark, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if tt.backup != nil {
+				if err := os.WriteFile(path+chromeBackupSuffix, tt.backup, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			b := NewChrome(path)
+			bookmarks, err := b.Bookmarks()
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expect error happens, but got response")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error got: %+v", err)
+			}
+			if diff := DiffBookmark(bookmarks, testChromeBookmarks); diff != "" {
+				t.Errorf("+want -got\n%+v", diff)
+			}
+
+			files := b.(LoadedFileSource).LoadedFiles()
+			if len(files) != 1 {
+				t.Fatalf("want a loaded file, got %d", len(files))
+			}
+			wantPath := path
+			if tt.wantBackup {
+				wantPath = path + chromeBackupSuffix
+			}
+			if files[0].Path != wantPath {
+				t.Errorf("want: %s, got: %s", wantPath, files[0].Path)
+			}
+			if tt.wantBackup != (files[0].Fallback != nil) {
+				t.Errorf("unexpected fallback reason: %v", files[0].Fallback)
+			}
+			if tt.wantErr != nil && !errors.Is(files[0].Fallback, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, files[0].Fallback)
+			}
+			if !errors.Is(files[0].Warning, tt.wantWarning) {
+				t.Errorf("want: %v, got: %v", tt.wantWarning, files[0].Warning)
+			}
+		})
+	}
+}
+
+// testChromiumBookmarks returns chrome test bookmarks as bookmarks of the chromium-based browser
+func testChromiumBookmarks(name bookmarkerName) Bookmarks {
+	bookmarks := make(Bookmarks, 0, len(testChromeBookmarks))
+	for _, b := range testChromeBookmarks {
+		c := *b
+		c.BookmarkerName = name
+		bookmarks = append(bookmarks, &c)
+	}
+	return bookmarks
+}
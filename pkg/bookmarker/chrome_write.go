@@ -0,0 +1,275 @@
+package bookmarker
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// chromeBackupSuffix is a suffix of the backup which chrome also creates next to the bookmark file
+const chromeBackupSuffix = ".bak"
+
+// ErrFolderNotFound is returned if a folder to add a bookmark does not exist
+var ErrFolderNotFound = errors.New("folder not found")
+
+// checksum returns md5 of ids, titles and urls of the roots the same as chrome validates bookmark files
+func (r *chromeBookmarkRoot) checksum() string {
+	h := md5.New()
+	for _, root := range []*chromeBookmarkEntry{r.Roots.BookmarkBar, r.Roots.Other, r.Roots.Synced} {
+		root.updateChecksum(h)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// updateChecksum writes the entry and its children into the hash.
+// Note: chrome hashes titles in UTF-16 little endian as they are string16
+func (entry *chromeBookmarkEntry) updateChecksum(h hash.Hash) {
+	if entry == nil {
+		return
+	}
+	h.Write([]byte(entry.ID))
+	title := utf16.Encode([]rune(entry.Name))
+	_ = binary.Write(h, binary.LittleEndian, title)
+	if entry.Type == "url" {
+		h.Write([]byte("url"))
+		h.Write([]byte(entry.URL))
+		return
+	}
+	h.Write([]byte("folder"))
+	for _, c := range entry.Children {
+		c.updateChecksum(h)
+	}
+}
+
+// AddChromeBookmark adds a bookmark of the url into the folder of a bookmark file of chrome.
+// See AddChromiumBookmark for details
+func AddChromeBookmark(bookmarkPath, folder, title, uri string) (*Bookmark, error) {
+	return AddChromiumBookmark(Chrome, bookmarkPath, folder, title, uri)
+}
+
+// AddChromiumBookmark adds a bookmark of the url into the folder of a bookmark file of chrome or chromium-based browsers.
+// The name is the browser which the returned bookmark comes from.
+// The folder is a path like `/Bookmarks Bar/work` which bookmarks have. It is the root of other bookmarks if empty.
+// Fields which are not known are kept as they are.
+// The previous file is copied to Bookmarks.bak, so the backup which the browser creates is replaced with it.
+// The file is not written if it is broken or the checksum does not match not to overwrite the valid backup.
+// Note: the browser overwrites the file with bookmarks in memory while running
+func AddChromiumBookmark(name bookmarkerName, bookmarkPath, folder, title, uri string) (*Bookmark, error) {
+	u, err := parseURL(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %s: %w", uri, err)
+	}
+	if title == "" {
+		title = uri
+	}
+
+	if _, err := loadChromeBookmarkFile(bookmarkPath); err != nil {
+		return nil, fmt.Errorf("refuse to write the broken bookmark file: %w", err)
+	}
+	data, err := os.ReadFile(bookmarkPath)
+	if err != nil {
+		return nil, err
+	}
+	// Note: decode the file into maps not to lose fields like meta_info and sync_metadata
+	doc := make(map[string]interface{})
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filepath.Base(bookmarkPath), err)
+	}
+	roots, ok := doc["roots"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("roots are not found in the bookmark file")
+	}
+
+	parent, err := findChromeFolder(roots, folder)
+	if err != nil {
+		return nil, err
+	}
+	guid, err := newGUID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	dateAdded := strconv.FormatInt(now.UnixMicro()+chromeEpochOffset, 10)
+	id := strconv.Itoa(maxChromeID(roots) + 1)
+	children, _ := parent["children"].([]interface{})
+	parent["children"] = append(children, map[string]interface{}{
+		"date_added":     dateAdded,
+		"date_last_used": "0",
+		"guid":           guid,
+		"id":             id,
+		"name":           title,
+		"type":           "url",
+		"url":            uri,
+	})
+	parent["date_modified"] = dateAdded
+
+	// Note: the checksum is calculated with the same entries as reading the file
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	root := new(chromeBookmarkRoot)
+	if err := json.Unmarshal(data, root); err != nil {
+		return nil, err
+	}
+	doc["checksum"] = root.checksum()
+	data, err = json.MarshalIndent(doc, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileWithBackup(bookmarkPath, data); err != nil {
+		return nil, err
+	}
+
+	parentName, _ := parent["name"].(string)
+	return &Bookmark{
+		BookmarkerName: name,
+		Folder:         chromeFolderPath(folder, parentName),
+		Title:          title,
+		URI:            uri,
+		Domain:         u.Host,
+		ID:             guid,
+		Added:          convertChromeTime(dateAdded),
+		Index:          len(children),
+	}, nil
+}
+
+// findChromeFolder returns a folder of the path whose first element is a name of the roots
+func findChromeFolder(roots map[string]interface{}, folder string) (map[string]interface{}, error) {
+	names := strings.FieldsFunc(folder, func(r rune) bool { return r == '/' })
+	if len(names) == 0 {
+		other, ok := roots["other"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: other bookmarks", ErrFolderNotFound)
+		}
+		return other, nil
+	}
+
+	var current map[string]interface{}
+	for _, key := range []string{"bookmark_bar", "other", "synced"} {
+		if root, ok := roots[key].(map[string]interface{}); ok && root["name"] == names[0] {
+			current = root
+			break
+		}
+	}
+	for _, name := range names[1:] {
+		if current == nil {
+			break
+		}
+		children, _ := current["children"].([]interface{})
+		current = nil
+		for _, c := range children {
+			if child, ok := c.(map[string]interface{}); ok && child["type"] == "folder" && child["name"] == name {
+				current = child
+				break
+			}
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: %s", ErrFolderNotFound, folder)
+	}
+	return current, nil
+}
+
+// chromeFolderPath returns the folder path of a bookmark in the folder
+func chromeFolderPath(folder, rootName string) string {
+	if strings.Trim(folder, "/") == "" {
+		return filepath.Join("/", rootName)
+	}
+	return filepath.Join("/", folder)
+}
+
+// maxChromeID returns the largest id of the nodes. ids are unique in the file
+func maxChromeID(node interface{}) int {
+	max := 0
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if s, ok := n["id"].(string); ok {
+			if id, err := strconv.Atoi(s); err == nil && id > max {
+				max = id
+			}
+		}
+		for _, v := range n {
+			if id := maxChromeID(v); id > max {
+				max = id
+			}
+		}
+	case []interface{}:
+		for _, v := range n {
+			if id := maxChromeID(v); id > max {
+				max = id
+			}
+		}
+	}
+	return max
+}
+
+// newGUID returns a random uuid of version 4 which chrome uses as guids
+func newGUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	s := hex.EncodeToString(b)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", s[:8], s[8:12], s[12:16], s[16:20], s[20:]), nil
+}
+
+// writeFileWithBackup replaces the file with the data atomically and keeps the previous file as the backup
+func writeFileWithBackup(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-")
+	if err != nil {
+		return err
+	}
+	cleanup := func() {
+		os.Remove(tmp.Name())
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		cleanup()
+		return err
+	}
+
+	if err := copyFile(path, path+chromeBackupSuffix); err != nil {
+		cleanup()
+		return fmt.Errorf("failed to back up %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package bookmarker
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChromeBookmarkChecksum(t *testing.T) {
+	// md5 of "1" + utf16le("ブックマーク") + "folder" + "3" + utf16le("Go") + "url" + "https://go.dev/" + "2" + "folder"
+	const want = "7c672c1f04165ef247a87710b7046c44"
+	root := new(chromeBookmarkRoot)
+	root.Roots.BookmarkBar = &chromeBookmarkEntry{
+		ID: "1", Name: "ブックマーク", Type: "folder",
+		Children: []*chromeBookmarkEntry{{ID: "3", Name: "Go", Type: "url", URL: "https://go.dev/"}},
+	}
+	root.Roots.Other = &chromeBookmarkEntry{ID: "2", Type: "folder"}
+	if got := root.checksum(); got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestAddChromeBookmark(t *testing.T) {
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(strings.Replace(string(data), "091f78e180d779522489bba763974d29", testChromeBookmarkChecksum, 1))
+	// fields which are not known are kept
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	doc["sync_metadata"] = "c3luYw=="
+	data, err = json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "Bookmarks")
+	writeTestFile(t, path, string(data))
+
+	tests := []struct {
+		name       string
+		folder     string
+		wantFolder string
+		wantErr    error
+	}{
+		{
+			name:       "nested folder",
+			folder:     "/Bookmarks Bar/1-hierarchy-a",
+			wantFolder: "/Bookmarks Bar/1-hierarchy-a",
+		},
+		{
+			name:       "other bookmarks by default",
+			wantFolder: "/Others",
+		},
+		{
+			name:    "unknown folder",
+			folder:  "/Bookmarks Bar/unknown",
+			wantErr: ErrFolderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			added, err := AddChromeBookmark(path, tt.folder, "Go", "https://go.dev/")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("want %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if added.BookmarkerName != Chrome || added.Folder != tt.wantFolder || time.Since(added.Added) > time.Minute {
+				t.Errorf("unexpected bookmark %+v", added)
+			}
+			if backup, err := os.ReadFile(path + ".bak"); err != nil || string(backup) != string(before) {
+				t.Errorf("the previous file should be backed up %v", err)
+			}
+
+			b := NewChrome(path).(*chromeBookmark)
+			bookmarks, err := b.Bookmarks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := b.bookmarkRoot.checksum(); got != b.bookmarkRoot.Checksum {
+				t.Errorf("want a valid checksum %s, got %s", got, b.bookmarkRoot.Checksum)
+			}
+			var found *Bookmark
+			for _, e := range bookmarks {
+				if e.ID == added.ID {
+					found = e
+				}
+			}
+			if found == nil || found.Folder != tt.wantFolder || found.URI != "https://go.dev/" || found.Index != added.Index {
+				t.Errorf("the added bookmark is not found %+v", found)
+			}
+
+			doc := make(map[string]interface{})
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, &doc); err != nil || doc["sync_metadata"] != "c3luYw==" {
+				t.Errorf("unknown fields should be kept %v", err)
+			}
+		})
+	}
+}
+
+func TestAddChromeBookmarkRefusesBrokenFiles(t *testing.T) {
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := strings.Replace(string(data), "091f78e180d779522489bba763974d29", testChromeBookmarkChecksum, 1)
+	tests := []struct {
+		name     string
+		bookmark string
+		wantErr  error
+	}{
+		{
+			name:     "checksum mismatch",
+			bookmark: string(data),
+			wantErr:  ErrChecksumMismatch,
+		},
+		{
+			name:     "half-written file",
+			bookmark: valid[:len(valid)/2],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Bookmarks")
+			writeTestFile(t, path, tt.bookmark)
+			writeTestFile(t, path+chromeBackupSuffix, valid)
+
+			_, err := AddChromeBookmark(path, "", "Go", "https://go.dev/")
+			if err == nil {
+				t.Fatalf("expect error happens, but got response")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("want %v, got %v", tt.wantErr, err)
+			}
+			if got, err := os.ReadFile(path); err != nil || string(got) != tt.bookmark {
+				t.Errorf("the file should not be written %v", err)
+			}
+			if backup, err := os.ReadFile(path + chromeBackupSuffix); err != nil || string(backup) != valid {
+				t.Errorf("the backup should not be overwritten %v", err)
+			}
+		})
+	}
+}
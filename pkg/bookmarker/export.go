@@ -0,0 +1,261 @@
+package bookmarker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is a file format of exported bookmarks
+type ExportFormat string
+
+const (
+	// ExportHTML is NETSCAPE-Bookmark-file-1 format which browsers can import
+	ExportHTML ExportFormat = "html"
+	// ExportJSON is a list of bookmarks in JSON
+	ExportJSON ExportFormat = "json"
+	// ExportCSV is a list of bookmarks in CSV with a header
+	ExportCSV ExportFormat = "csv"
+	// ExportMarkdown is an outline of bookmarks grouped by folder
+	ExportMarkdown ExportFormat = "markdown"
+)
+
+// ExportFormats returns supported export formats
+func ExportFormats() []ExportFormat {
+	return []ExportFormat{ExportHTML, ExportJSON, ExportCSV, ExportMarkdown}
+}
+
+// Export writes the bookmarks into w in the format.
+// History, open tabs and search engines are not written as importing them makes junk bookmarks
+func (b Bookmarks) Export(w io.Writer, format ExportFormat) error {
+	b = b.exportable()
+	switch format {
+	case ExportHTML:
+		return b.exportHTML(w)
+	case ExportJSON:
+		return b.exportJSON(w)
+	case ExportCSV:
+		return b.exportCSV(w)
+	case ExportMarkdown:
+		return b.exportMarkdown(w)
+	}
+	return fmt.Errorf("unsupported export format: %s", format)
+}
+
+// exportable returns entries which are bookmarks
+func (b Bookmarks) exportable() Bookmarks {
+	bookmarks := make(Bookmarks, 0, len(b))
+	for _, e := range b {
+		if e.History || e.Tab != nil || e.SearchEngine {
+			continue
+		}
+		bookmarks = append(bookmarks, e)
+	}
+	return bookmarks
+}
+
+// exportFolder is a folder of exported bookmarks. children keep the order of bookmarks
+type exportFolder struct {
+	name     string
+	children []*exportEntry
+	folders  map[string]*exportFolder
+}
+
+// exportEntry is a folder or a bookmark
+type exportEntry struct {
+	folder   *exportFolder
+	bookmark *Bookmark
+}
+
+// folderTree builds a folder tree from Bookmark.Folder
+func (b Bookmarks) folderTree() *exportFolder {
+	root := &exportFolder{folders: make(map[string]*exportFolder)}
+	for _, bookmark := range b {
+		f := root
+		for _, name := range strings.Split(strings.Trim(bookmark.Folder, "/"), "/") {
+			if name == "" {
+				continue
+			}
+			child, ok := f.folders[name]
+			if !ok {
+				child = &exportFolder{name: name, folders: make(map[string]*exportFolder)}
+				f.folders[name] = child
+				f.children = append(f.children, &exportEntry{folder: child})
+			}
+			f = child
+		}
+		f.children = append(f.children, &exportEntry{bookmark: bookmark})
+	}
+	return root
+}
+
+const exportHTMLHeader = `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+`
+
+func (b Bookmarks) exportHTML(w io.Writer) error {
+	buf := new(strings.Builder)
+	buf.WriteString(exportHTMLHeader)
+	writeHTMLFolder(buf, b.folderTree(), 0)
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func writeHTMLFolder(buf *strings.Builder, f *exportFolder, depth int) {
+	indent := strings.Repeat("    ", depth)
+	buf.WriteString(indent + "<DL><p>\n")
+	for _, e := range f.children {
+		if e.folder != nil {
+			buf.WriteString(indent + "    <DT><H3>" + html.EscapeString(e.folder.name) + "</H3>\n")
+			writeHTMLFolder(buf, e.folder, depth+1)
+			continue
+		}
+
+		bookmark := e.bookmark
+		buf.WriteString(indent + `    <DT><A HREF="` + html.EscapeString(bookmark.URI) + `"`)
+		if !bookmark.Added.IsZero() {
+			buf.WriteString(` ADD_DATE="` + strconv.FormatInt(bookmark.Added.Unix(), 10) + `"`)
+		}
+		if !bookmark.Modified.IsZero() {
+			buf.WriteString(` LAST_MODIFIED="` + strconv.FormatInt(bookmark.Modified.Unix(), 10) + `"`)
+		}
+		if len(bookmark.Tags) > 0 {
+			buf.WriteString(` TAGS="` + html.EscapeString(strings.Join(bookmark.Tags, ",")) + `"`)
+		}
+		if bookmark.Keyword != "" {
+			buf.WriteString(` SHORTCUTURL="` + html.EscapeString(bookmark.Keyword) + `"`)
+		}
+		buf.WriteString(">" + html.EscapeString(bookmark.Title) + "</A>\n")
+		if bookmark.Description != "" {
+			buf.WriteString(indent + "    <DD>" + html.EscapeString(bookmark.Description) + "\n")
+		}
+	}
+	buf.WriteString(indent + "</DL><p>\n")
+}
+
+// exportBookmark is a bookmark of JSON format
+type exportBookmark struct {
+	Browser     string     `json:"browser"`
+	Profile     string     `json:"profile,omitempty"`
+	Folder      string     `json:"folder"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Domain      string     `json:"domain"`
+	ID          string     `json:"id,omitempty"`
+	Added       *time.Time `json:"added,omitempty"`
+	Modified    *time.Time `json:"modified,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Keyword     string     `json:"keyword,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+func (b Bookmarks) exportJSON(w io.Writer) error {
+	bookmarks := make([]*exportBookmark, 0, len(b))
+	for _, bookmark := range b {
+		bookmarks = append(bookmarks, &exportBookmark{
+			Browser:     string(bookmark.BookmarkerName),
+			Profile:     bookmark.Profile,
+			Folder:      bookmark.Folder,
+			Title:       bookmark.Title,
+			URL:         bookmark.URI,
+			Domain:      bookmark.Domain,
+			ID:          bookmark.ID,
+			Added:       timeOrNil(bookmark.Added),
+			Modified:    timeOrNil(bookmark.Modified),
+			Tags:        bookmark.Tags,
+			Keyword:     bookmark.Keyword,
+			Description: bookmark.Description,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bookmarks)
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+var exportCSVHeader = []string{
+	"browser", "profile", "folder", "title", "url", "domain",
+	"tags", "keyword", "description", "added", "modified",
+}
+
+func (b Bookmarks) exportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for _, bookmark := range b {
+		record := []string{
+			string(bookmark.BookmarkerName),
+			bookmark.Profile,
+			bookmark.Folder,
+			bookmark.Title,
+			bookmark.URI,
+			bookmark.Domain,
+			strings.Join(bookmark.Tags, ","),
+			bookmark.Keyword,
+			bookmark.Description,
+			formatExportTime(bookmark.Added),
+			formatExportTime(bookmark.Modified),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+// markdownURIEscaper percent-encodes characters which end a link destination in angle brackets.
+var markdownURIEscaper = strings.NewReplacer(" ", "%20", "\n", "%0A", "\r", "%0D", "<", "%3C", ">", "%3E")
+
+func (b Bookmarks) exportMarkdown(w io.Writer) error {
+	buf := new(strings.Builder)
+	buf.WriteString("# Bookmarks\n\n")
+	writeMarkdownFolder(buf, b.folderTree(), 0)
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func writeMarkdownFolder(buf *strings.Builder, f *exportFolder, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, e := range f.children {
+		if e.folder != nil {
+			buf.WriteString(indent + "- " + markdownEscaper.Replace(e.folder.name) + "\n")
+			writeMarkdownFolder(buf, e.folder, depth+1)
+			continue
+		}
+
+		bookmark := e.bookmark
+		buf.WriteString(indent + "- [" + markdownEscaper.Replace(bookmark.Title) + "](<" + markdownURIEscaper.Replace(bookmark.URI) + ">)")
+		if bookmark.Description != "" {
+			buf.WriteString(" - " + bookmark.Description)
+		}
+		buf.WriteString("\n")
+	}
+}
@@ -0,0 +1,183 @@
+package bookmarker
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testExportBookmarks = Bookmarks{
+	&Bookmark{
+		BookmarkerName: Chrome,
+		Profile:        "Default",
+		Folder:         "/Bookmarks Bar",
+		Title:          "Google",
+		Domain:         "www.google.com",
+		URI:            "https://www.google.com/",
+		Added:          time.Date(2019, 12, 9, 14, 8, 23, 0, time.UTC),
+		Tags:           []string{"search", "daily"},
+	},
+	&Bookmark{
+		BookmarkerName: Firefox,
+		Folder:         "/Bookmarks Bar/dev [test]",
+		Title:          "GitHub",
+		Domain:         "github.com",
+		URI:            "https://github.com/",
+		Keyword:        "gh",
+		Description:    "Where the world builds software",
+	},
+	&Bookmark{
+		BookmarkerName: Safari,
+		Folder:         "/",
+		Title:          "Amazon",
+		Domain:         "www.amazon.com",
+		URI:            "https://www.amazon.com/",
+	},
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ExportFormat
+		want   string
+	}{
+		{
+			name:   "json",
+			format: ExportJSON,
+			want: `[
+  {
+    "browser": "chrome",
+    "profile": "Default",
+    "folder": "/Bookmarks Bar",
+    "title": "Google",
+    "url": "https://www.google.com/",
+    "domain": "www.google.com",
+    "added": "2019-12-09T14:08:23Z",
+    "tags": [
+      "search",
+      "daily"
+    ]
+  },
+  {
+    "browser": "firefox",
+    "folder": "/Bookmarks Bar/dev [test]",
+    "title": "GitHub",
+    "url": "https://github.com/",
+    "domain": "github.com",
+    "keyword": "gh",
+    "description": "Where the world builds software"
+  },
+  {
+    "browser": "safari",
+    "folder": "/",
+    "title": "Amazon",
+    "url": "https://www.amazon.com/",
+    "domain": "www.amazon.com"
+  }
+]
+`,
+		},
+		{
+			name:   "csv",
+			format: ExportCSV,
+			want: `browser,profile,folder,title,url,domain,tags,keyword,description,added,modified
+chrome,Default,/Bookmarks Bar,Google,https://www.google.com/,www.google.com,"search,daily",,,2019-12-09T14:08:23Z,
+firefox,,/Bookmarks Bar/dev [test],GitHub,https://github.com/,github.com,,gh,Where the world builds software,,
+safari,,/,Amazon,https://www.amazon.com/,www.amazon.com,,,,,
+`,
+		},
+		{
+			name:   "markdown",
+			format: ExportMarkdown,
+			want: `# Bookmarks
+
+- Bookmarks Bar
+  - [Google](<https://www.google.com/>)
+  - dev \[test\]
+    - [GitHub](<https://github.com/>) - Where the world builds software
+- [Amazon](<https://www.amazon.com/>)
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := testExportBookmarks.Export(buf, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("-want +got\n%+v", diff)
+			}
+		})
+	}
+
+	if err := testExportBookmarks.Export(new(bytes.Buffer), "unknown"); err == nil {
+		t.Errorf("expect error happens if the format is not supported")
+	}
+}
+
+func TestExportMarkdownEscapesLinks(t *testing.T) {
+	bookmarks := Bookmarks{
+		{BookmarkerName: Chrome, Folder: "/", Title: "[draft] notes", URI: "https://example.com/a b>c\nd\re<f"},
+	}
+	want := "# Bookmarks\n\n- [\\[draft\\] notes](<https://example.com/a%20b%3Ec%0Ad%0De%3Cf>)\n"
+
+	buf := new(bytes.Buffer)
+	if err := bookmarks.Export(buf, ExportMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+}
+
+func TestExportOnlyBookmarks(t *testing.T) {
+	mixed := append(Bookmarks{
+		{BookmarkerName: Chrome, Title: "Go", URI: "https://go.dev/", Domain: "go.dev", History: true},
+		{BookmarkerName: FirefoxTabs, Title: "Go Packages", URI: "https://pkg.go.dev/", Domain: "pkg.go.dev", Tab: &Tab{Window: 1, Index: 1}},
+		{BookmarkerName: Chrome, Folder: SearchEnginesFolder, Title: "Google", URI: "https://www.google.com/search?q=%s", Domain: "www.google.com", Keyword: "g", SearchEngine: true},
+	}, testExportBookmarks...)
+
+	for _, format := range ExportFormats() {
+		t.Run(string(format), func(t *testing.T) {
+			want, got := new(bytes.Buffer), new(bytes.Buffer)
+			if err := testExportBookmarks.Export(want, format); err != nil {
+				t.Fatal(err)
+			}
+			if err := mixed.Export(got, format); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+				t.Errorf("-want +got\n%+v", diff)
+			}
+		})
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := testExportBookmarks.Export(buf, ExportHTML); err != nil {
+		t.Fatal(err)
+	}
+
+	// exported html can be imported again
+	got, err := parseBookmarkHTML(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(Bookmarks, 0, len(testExportBookmarks))
+	for _, b := range testExportBookmarks {
+		c := *b
+		c.BookmarkerName = HTML
+		c.Profile = ""
+		want = append(want, &c)
+	}
+	for _, b := range got {
+		b.Index = 0
+	}
+	if diff := DiffBookmark(want, got); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+}
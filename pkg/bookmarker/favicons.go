@@ -0,0 +1,326 @@
+package bookmarker
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FaviconSource is implemented by bookmarkers which can read site icons from favicon databases of browsers.
+// Favicons returns image data by domain
+type FaviconSource interface {
+	Favicons() (map[string][]byte, error)
+}
+
+// faviconFileSource is implemented by favicon sources which read files other than the bookmark files.
+// The files are fingerprinted only if favicons are enabled
+type faviconFileSource interface {
+	faviconFiles() []string
+}
+
+// WithFavicons if called, site icons of browsers are written into a per-domain icon cache in dir
+// and the paths are attached to bookmarks
+func WithFavicons(dir string) Option {
+	return func(m *Manager) error {
+		m.faviconDir = dir
+		return nil
+	}
+}
+
+// readFavicons returns favicons of the bookmarker.
+// nil is returned if favicons can not be read as they are optional
+func readFavicons(b Bookmarker) map[string][]byte {
+	fs, ok := b.(FaviconSource)
+	if !ok {
+		return nil
+	}
+	favicons, err := fs.Favicons()
+	if err != nil {
+		return nil
+	}
+	return favicons
+}
+
+// faviconsByDomain reads favicons of urls from the query of which rows are a page url and image data.
+// The first icon of a domain wins, so the query orders better icons first
+func faviconsByDomain(path, query string) (map[string][]byte, error) {
+	db, closeDB, err := openSQLiteSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favicons: %w", err)
+	}
+	defer rows.Close()
+
+	favicons := make(map[string][]byte)
+	for rows.Next() {
+		var pageURL string
+		var data []byte
+		if err := rows.Scan(&pageURL, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan favicons: %w", err)
+		}
+		addFavicon(favicons, pageURL, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read favicons: %w", err)
+	}
+	return favicons, nil
+}
+
+func addFavicon(favicons map[string][]byte, pageURL string, data []byte) {
+	u, err := parseURL(pageURL)
+	if err != nil || len(data) == 0 {
+		return
+	}
+	domain := strings.ToLower(u.Host)
+	if _, ok := favicons[domain]; !ok {
+		favicons[domain] = data
+	}
+}
+
+// faviconPath returns the Favicons database next to the bookmark file
+func (b *chromeBookmark) faviconPath() string {
+	return filepath.Join(filepath.Dir(b.bookmarkPath), "Favicons")
+}
+
+func (b *chromeBookmark) faviconFiles() []string {
+	return []string{b.faviconPath(), b.faviconPath() + "-wal"}
+}
+
+// Favicons returns the largest icons of pages in the Favicons database next to the bookmark file
+func (b *chromeBookmark) Favicons() (map[string][]byte, error) {
+	const query = `
+SELECT m.page_url, b.image_data
+FROM icon_mapping AS m
+JOIN favicon_bitmaps AS b ON b.icon_id = m.icon_id
+WHERE b.image_data IS NOT NULL
+ORDER BY b.width DESC`
+	return faviconsByDomain(b.faviconPath(), query)
+}
+
+// faviconPath returns favicons.sqlite next to places.sqlite
+func (b *firefoxPlacesBookmark) faviconPath() string {
+	return filepath.Join(filepath.Dir(b.placesPath), "favicons.sqlite")
+}
+
+func (b *firefoxPlacesBookmark) faviconFiles() []string {
+	return []string{b.faviconPath(), b.faviconPath() + "-wal"}
+}
+
+// Favicons returns the largest icons of pages in favicons.sqlite next to places.sqlite.
+// Icons of the site root like /favicon.ico are used if pages have no icons
+func (b *firefoxPlacesBookmark) Favicons() (map[string][]byte, error) {
+	const query = `
+SELECT url, data FROM (
+	SELECT p.page_url AS url, i.data AS data, i.width AS width, 0 AS root
+	FROM moz_pages_w_icons AS p
+	JOIN moz_icons_to_pages AS ip ON ip.page_id = p.id
+	JOIN moz_icons AS i ON i.id = ip.icon_id
+	WHERE i.data IS NOT NULL
+	UNION ALL
+	SELECT icon_url, data, width, 1
+	FROM moz_icons
+	WHERE root = 1 AND data IS NOT NULL
+)
+ORDER BY root, width DESC`
+	return faviconsByDomain(b.faviconPath(), query)
+}
+
+func (b *firefoxFallbackBookmark) faviconFiles() []string {
+	if fs, ok := b.places.(faviconFileSource); ok {
+		return fs.faviconFiles()
+	}
+	return nil
+}
+
+// Favicons returns icons of favicons.sqlite. Bookmark backups have no icons
+func (b *firefoxFallbackBookmark) Favicons() (map[string][]byte, error) {
+	fs, ok := b.places.(FaviconSource)
+	if !ok {
+		return nil, fmt.Errorf("firefox error: places.sqlite is not available")
+	}
+	return fs.Favicons()
+}
+
+// faviconCacheDir returns the favicon cache of safari next to the bookmark file
+func (b *safariBookmark) faviconCacheDir() string {
+	return filepath.Join(filepath.Dir(b.bookmarkPath), "Favicon Cache")
+}
+
+func (b *safariBookmark) faviconFiles() []string {
+	path := filepath.Join(b.faviconCacheDir(), "favicons.db")
+	return []string{path, path + "-wal"}
+}
+
+// Favicons returns icons of the favicon cache of safari.
+// favicons.db maps pages to icon urls and the images are saved in files named by md5 of the icon urls
+func (b *safariBookmark) Favicons() (map[string][]byte, error) {
+	cacheDir := b.faviconCacheDir()
+	db, closeDB, err := openSQLiteSnapshot(filepath.Join(cacheDir, "favicons.db"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	const query = `
+SELECT p.url, i.url
+FROM page_url AS p
+JOIN icon_info AS i ON i.uuid = p.uuid
+ORDER BY i.width DESC`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favicons: %w", err)
+	}
+	defer rows.Close()
+
+	favicons := make(map[string][]byte)
+	for rows.Next() {
+		var pageURL, iconURL string
+		if err := rows.Scan(&pageURL, &iconURL); err != nil {
+			return nil, fmt.Errorf("failed to scan favicons: %w", err)
+		}
+		sum := md5.Sum([]byte(iconURL))
+		data, err := os.ReadFile(filepath.Join(cacheDir, "favicons", strings.ToUpper(hex.EncodeToString(sum[:]))))
+		if err != nil {
+			continue
+		}
+		addFavicon(favicons, pageURL, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read favicons: %w", err)
+	}
+	return favicons, nil
+}
+
+// faviconExts are extensions of icon files by the content type
+var faviconExts = map[string]string{
+	"image/png":                ".png",
+	"image/jpeg":               ".jpg",
+	"image/gif":                ".gif",
+	"image/webp":               ".webp",
+	"image/x-icon":             ".ico",
+	"image/vnd.microsoft.icon": ".ico",
+	"image/bmp":                ".bmp",
+}
+
+// faviconExt returns an extension of the icon file. svg is detected as text by the sniffing
+func faviconExt(data []byte) string {
+	if ext, ok := faviconExts[http.DetectContentType(data)]; ok {
+		return ext
+	}
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if strings.Contains(string(head), "<svg") {
+		return ".svg"
+	}
+	return ""
+}
+
+// storeFavicons writes the favicons into the icon cache.
+// Icons which are changed in browsers are replaced and the others are not written again
+func storeFavicons(dir string, favicons map[string][]byte) error {
+	if len(favicons) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	cached, err := cachedFavicons(dir)
+	if err != nil {
+		return err
+	}
+
+	for domain, data := range favicons {
+		ext := faviconExt(data)
+		if ext == "" || strings.ContainsAny(domain, `/\`) {
+			continue
+		}
+		path := filepath.Join(dir, domain+ext)
+		if cached[domain] == path && sameContent(path, data) {
+			continue
+		}
+		// Note: write a temporary file and rename it not to show a broken icon
+		tmp, err := os.CreateTemp(dir, ".favicon-")
+		if err != nil {
+			return err
+		}
+		_, err = tmp.Write(data)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(tmp.Name(), path)
+		}
+		if err != nil {
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to store a favicon of %s: %w", domain, err)
+		}
+		// Note: the icon of the other format is stale
+		if old, ok := cached[domain]; ok && old != path {
+			os.Remove(old)
+		}
+	}
+	return nil
+}
+
+// sameContent returns true if the file has the data
+func sameContent(path string, data []byte) bool {
+	b, err := os.ReadFile(path)
+	return err == nil && bytes.Equal(b, data)
+}
+
+// cachedFavicons returns paths of icons in the icon cache by domain
+func cachedFavicons(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	icons := make(map[string]string, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		domain := strings.TrimSuffix(name, filepath.Ext(name))
+		icons[domain] = filepath.Join(dir, name)
+	}
+	return icons, nil
+}
+
+// attachFavicons writes the favicons into the icon cache and sets paths of cached icons to the bookmarks by domain.
+// The bookmarks are returned as they are if the icon cache is not available as it is optional
+func attachFavicons(bookmarks Bookmarks, dir string, favicons ...map[string][]byte) Bookmarks {
+	// Note: icons of the first bookmarker win not to replace them with icons of the others
+	merged := make(map[string][]byte)
+	for _, f := range favicons {
+		for domain, data := range f {
+			if _, ok := merged[domain]; !ok {
+				merged[domain] = data
+			}
+		}
+	}
+	if err := storeFavicons(dir, merged); err != nil {
+		return bookmarks
+	}
+	icons, err := cachedFavicons(dir)
+	if err != nil {
+		return bookmarks
+	}
+
+	for _, bookmark := range bookmarks {
+		bookmark.Icon = icons[strings.ToLower(bookmark.Domain)]
+	}
+	return bookmarks
+}
@@ -0,0 +1,171 @@
+package bookmarker
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	testICO = []byte("\x00\x00\x01\x00\x01\x00\x10\x10")
+)
+
+func TestManagerBookmarksWithFavicons(t *testing.T) {
+	dir := t.TempDir()
+	bookmarkPath := filepath.Join(dir, "Bookmarks")
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, bookmarkPath, string(data))
+	db := openTestDB(t, filepath.Join(dir, "Favicons"))
+	if _, err := db.Exec(`
+CREATE TABLE icon_mapping (id INTEGER PRIMARY KEY, page_url LONGVARCHAR, icon_id INTEGER);
+CREATE TABLE favicon_bitmaps (id INTEGER PRIMARY KEY, icon_id INTEGER, image_data BLOB, width INTEGER);`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO icon_mapping (page_url, icon_id) VALUES (?, 1), (?, 2)`,
+		"https://github.com/", "https://www.google.com/search"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO favicon_bitmaps (icon_id, image_data, width) VALUES (1, ?, 16), (1, ?, 32), (2, ?, 16)`,
+		testICO, testPNG, []byte("not an image")); err != nil {
+		t.Fatal(err)
+	}
+
+	placesPath := createTestFirefoxPlaces(t)
+	db = openTestDB(t, filepath.Join(filepath.Dir(placesPath), "favicons.sqlite"))
+	if _, err := db.Exec(`
+CREATE TABLE moz_icons (id INTEGER PRIMARY KEY, icon_url TEXT, width INTEGER, root INTEGER, data BLOB);
+CREATE TABLE moz_pages_w_icons (id INTEGER PRIMARY KEY, page_url TEXT);
+CREATE TABLE moz_icons_to_pages (page_id INTEGER, icon_id INTEGER);`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO moz_icons (id, icon_url, width, root, data) VALUES
+		(1, 'https://github.com/favicon.ico', 16, 1, ?), (2, 'https://www.amazon.com/favicon.ico', 16, 1, ?)`,
+		testICO, testICO); err != nil {
+		t.Fatal(err)
+	}
+
+	iconDir := filepath.Join(t.TempDir(), "favicons")
+	m, err := New(WithFavicons(iconDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := m.(*Manager)
+	manager.add(Chrome, testProfile, NewChrome(bookmarkPath))
+	manager.add(Firefox, testProfile, &firefoxFallbackBookmark{places: NewFirefoxPlaces(placesPath)})
+	bookmarks, err := manager.Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	icons := make(map[string]string)
+	for _, b := range bookmarks {
+		icons[b.Domain] = b.Icon
+	}
+	want := map[string]string{
+		// chrome comes first by the name and the largest icon wins
+		"github.com":     filepath.Join(iconDir, "github.com.png"),
+		"www.amazon.com": filepath.Join(iconDir, "www.amazon.com.ico"),
+	}
+	for domain, path := range want {
+		if icons[domain] != path {
+			t.Errorf("want %s for %s, got %s", path, domain, icons[domain])
+		}
+	}
+	if icons["www.google.com"] != "" {
+		t.Errorf("unknown images should not be cached %s", icons["www.google.com"])
+	}
+	if got, err := os.ReadFile(want["github.com"]); err != nil || string(got) != string(testPNG) {
+		t.Errorf("unexpected icon %v %v", got, err)
+	}
+
+	// the icon cache is kept if browsers have no icons
+	for _, path := range []string{filepath.Join(dir, "Favicons"), filepath.Join(filepath.Dir(placesPath), "favicons.sqlite")} {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bookmarks, err = manager.Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bookmarks {
+		if b.Domain == "github.com" && b.Icon != want["github.com"] {
+			t.Errorf("want a cached icon, got %s", b.Icon)
+		}
+	}
+}
+
+func TestSafariFavicons(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "Favicon Cache")
+	if err := os.MkdirAll(filepath.Join(cacheDir, "favicons"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	db := openTestDB(t, filepath.Join(cacheDir, "favicons.db"))
+	if _, err := db.Exec(`
+CREATE TABLE page_url (uuid TEXT, url TEXT);
+CREATE TABLE icon_info (uuid TEXT, url TEXT, width REAL);`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO page_url (uuid, url) VALUES ('1', 'https://go.dev/doc/'), ('2', 'https://GitHub.com/')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO icon_info (uuid, url, width) VALUES ('1', 'https://go.dev/favicon.ico', 32), ('2', 'https://github.com/favicon.ico', 32)`); err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum([]byte("https://go.dev/favicon.ico"))
+	writeTestFile(t, filepath.Join(cacheDir, "favicons", strings.ToUpper(hex.EncodeToString(sum[:]))), string(testPNG))
+
+	got, err := NewSafari(filepath.Join(dir, "Bookmarks.plist")).(FaviconSource).Favicons()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Note: the icon of github.com is not cached
+	if diff := cmp.Diff(map[string][]byte{"go.dev": testPNG}, got); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+}
+
+func TestStoreFavicons(t *testing.T) {
+	dir := t.TempDir()
+	if err := storeFavicons(dir, map[string][]byte{"go.dev": testICO}); err != nil {
+		t.Fatal(err)
+	}
+	ico := filepath.Join(dir, "go.dev.ico")
+	if got, err := os.ReadFile(ico); err != nil || string(got) != string(testICO) {
+		t.Fatalf("unexpected icon %v %v", got, err)
+	}
+
+	// the icon which is changed in the browser is replaced by the new format
+	if err := storeFavicons(dir, map[string][]byte{"go.dev": testPNG}); err != nil {
+		t.Fatal(err)
+	}
+	icons, err := cachedFavicons(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"go.dev": filepath.Join(dir, "go.dev.png")}, icons); diff != "" {
+		t.Errorf("stale icons should be removed -want +got\n%s", diff)
+	}
+
+	// the same icon is not written again
+	info, err := os.Stat(icons["go.dev"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storeFavicons(dir, map[string][]byte{"go.dev": testPNG}); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := os.Stat(icons["go.dev"]); err != nil || !os.SameFile(info, after) {
+		t.Errorf("the icon should not be written again")
+	}
+}
@@ -0,0 +1,137 @@
+package bookmarker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// FileSource is implemented by bookmarkers which read bookmarks from files
+type FileSource interface {
+	// Files returns paths of files and directories which bookmarks are read from
+	Files() []string
+}
+
+// volatileFileSource is implemented by bookmarkers which read files which browsers write on every visit
+// like places.sqlite of firefox and history databases
+type volatileFileSource interface {
+	volatileFiles() []string
+}
+
+// Fingerprint is a state of a file to detect changes of bookmarks
+type Fingerprint struct {
+	Path string `json:"path"`
+	// Size is -1 if the file does not exist
+	Size int64 `json:"size"`
+	// ModTime is unix time in nanoseconds
+	ModTime int64 `json:"mod_time"`
+	// Hash is sha256 of the file. empty for directories and large files
+	Hash string `json:"hash,omitempty"`
+	// Volatile is true if browsers write the file on every visit even if bookmarks are not changed
+	Volatile bool `json:"volatile,omitempty"`
+}
+
+// Fingerprints is a list of Fingerprint sorted by path
+type Fingerprints []*Fingerprint
+
+// maxHashSize is a maximum file size to calculate a hash. larger files are compared by size and mtime
+const maxHashSize = 4 << 20
+
+// GetFingerprints returns fingerprints of files which the bookmarker reads.
+// Bookmarkers which do not implement FileSource have no fingerprints
+func GetFingerprints(b Bookmarker) (Fingerprints, error) {
+	fb, ok := b.(FileSource)
+	if !ok {
+		return Fingerprints{}, nil
+	}
+
+	volatile := make(map[string]bool)
+	if vs, ok := b.(volatileFileSource); ok {
+		for _, path := range vs.volatileFiles() {
+			volatile[path] = true
+		}
+	}
+
+	paths := fb.Files()
+	sort.Strings(paths)
+	fps := make(Fingerprints, 0, len(paths))
+	for i, path := range paths {
+		if i > 0 && paths[i-1] == path {
+			continue
+		}
+		fp, err := newFingerprint(path)
+		if err != nil {
+			return nil, err
+		}
+		fp.Volatile = volatile[path]
+		fps = append(fps, fp)
+	}
+	return fps, nil
+}
+
+func newFingerprint(path string) (*Fingerprint, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Note: a file which appears later is also a change
+		return &Fingerprint{Path: path, Size: -1}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &Fingerprint{
+		Path:    path,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+	}
+	if info.IsDir() || info.Size() > maxHashSize {
+		return fp, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	fp.Hash = hex.EncodeToString(h.Sum(nil))
+	return fp, nil
+}
+
+// Equal returns true if all files have the same state
+func (fps Fingerprints) Equal(other Fingerprints) bool {
+	if len(fps) != len(other) {
+		return false
+	}
+	for i := range fps {
+		if *fps[i] != *other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualExceptVolatile returns true if all files have the same state except for volatile files.
+// Volatile files are compared only by paths as browsers write them on every visit
+func (fps Fingerprints) EqualExceptVolatile(other Fingerprints) bool {
+	if len(fps) != len(other) {
+		return false
+	}
+	for i := range fps {
+		if fps[i].Path != other[i].Path || fps[i].Volatile != other[i].Volatile {
+			return false
+		}
+		if !fps[i].Volatile && *fps[i] != *other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package bookmarker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Bookmarks")
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, path, string(data))
+
+	m := &Manager{
+		bookmarkers: make(map[bookmarkerName][]*sourceBookmark),
+	}
+	m.add(Chrome, testProfile, NewChrome(path))
+	m.add(HTML, "", NewHTML(filepath.Join(dir, "bookmarks.html")))
+
+	before, err := GetFingerprints(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 2 {
+		t.Fatalf("want 2 fingerprints, got %d", len(before))
+	}
+	if before[0].Hash == "" || before[1].Size != -1 {
+		t.Errorf("unexpected fingerprints %+v %+v", before[0], before[1])
+	}
+
+	same, err := GetFingerprints(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !before.Equal(same) {
+		t.Errorf("fingerprints should be equal if files are not changed")
+	}
+
+	// a bookmark file is changed and a new file appears
+	writeTestFile(t, path, string(data)+"\n")
+	writeTestFile(t, filepath.Join(dir, "bookmarks.html"), "")
+	after, err := GetFingerprints(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Equal(after) {
+		t.Errorf("fingerprints should be changed if files are changed")
+	}
+
+	// third-party bookmarkers may not implement FileSource
+	fps, err := GetFingerprints(&noNameBookmark{})
+	if err != nil || len(fps) != 0 {
+		t.Errorf("want no fingerprints, got %v %v", fps, err)
+	}
+}
+
+func TestGetFingerprintsVisitsAndFavicons(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Bookmarks")
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, path, string(data))
+	writeTestFile(t, filepath.Join(dir, "History"), "history")
+	writeTestFile(t, filepath.Join(dir, "Favicons"), "favicons")
+
+	tests := []struct {
+		name    string
+		options []Option
+		changed string
+		want    bool
+	}{
+		{
+			name:    "history is not fingerprinted without visits",
+			changed: "History",
+		},
+		{
+			name:    "history is fingerprinted with visits",
+			options: []Option{WithVisits()},
+			changed: "History",
+			want:    true,
+		},
+		{
+			name:    "favicons are not fingerprinted without favicons",
+			changed: "Favicons",
+		},
+		{
+			name:    "favicons are fingerprinted with favicons",
+			options: []Option{WithFavicons(t.TempDir())},
+			changed: "Favicons",
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(append(tt.options, WithBookmarker(string(Chrome), NewChrome(path)))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, err := GetFingerprints(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeTestFile(t, filepath.Join(dir, tt.changed), tt.name)
+			after, err := GetFingerprints(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := !before.Equal(after); got != tt.want {
+				t.Errorf("want changed %v, got %v", tt.want, got)
+			}
+			// browsers write history and favicon databases on every visit
+			if !before.EqualExceptVolatile(after) {
+				t.Errorf("history and favicons should be volatile")
+			}
+		})
+	}
+}
+
+func TestGetFingerprintsVolatile(t *testing.T) {
+	dir := t.TempDir()
+	places := filepath.Join(dir, "places.sqlite")
+	backup := filepath.Join(dir, "bookmarkbackups", "bookmarks.jsonlz4")
+	writeTestFile(t, places, "places")
+	if err := os.Mkdir(filepath.Dir(backup), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		bookmarkers: make(map[bookmarkerName][]*sourceBookmark),
+	}
+	m.add(Firefox, testProfile, &firefoxFallbackBookmark{
+		places: NewFirefoxPlaces(places),
+		backup: NewFirefox(backup),
+	})
+
+	before, err := GetFingerprints(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fp := range before {
+		if want := fp.Path == places || fp.Path == places+"-wal"; fp.Volatile != want {
+			t.Errorf("want volatile %v, got %+v", want, fp)
+		}
+	}
+
+	// firefox writes places.sqlite on every visit
+	writeTestFile(t, places, "visited")
+	after, err := GetFingerprints(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Equal(after) || !before.EqualExceptVolatile(after) {
+		t.Errorf("only volatile files should be changed")
+	}
+
+	writeTestFile(t, backup, "backup")
+	after, err = GetFingerprints(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.EqualExceptVolatile(after) {
+		t.Errorf("fingerprints should be changed if the bookmark backup is changed")
+	}
+}
@@ -29,9 +29,20 @@ type firefoxBookmarkEntry struct {
 		Flags   int    `json:"flags"`
 	} `json:"annos,omitempty"`
 	URI      string                  `json:"uri,omitempty"`
+	Tags     string                  `json:"tags,omitempty"`
+	Keyword  string                  `json:"keyword,omitempty"`
 	Children []*firefoxBookmarkEntry `json:"children,omitempty"`
+	// placeID is an id of the url in moz_places. bookmark backups have no ids
+	placeID int
 }
 
+// firefoxTagsRootGUID is a guid of the root folder of tags.
+// bookmarks in the tag folders duplicate tagged bookmarks, so they are not searched
+const firefoxTagsRootGUID = "tags________"
+
+// firefoxDescriptionAnno is an annotation name of a bookmark description
+const firefoxDescriptionAnno = "bookmarkProperties/description"
+
 // firefoxBookmarkRoot has a single entry as root which has childrens
 type firefoxBookmarkRoot struct {
 	root firefoxBookmarkEntry
@@ -76,6 +87,12 @@ func (b *firefoxBookmark) load() error {
 	return json.NewDecoder(r).Decode(&b.bookmarkRoot.root)
 }
 
+// Files returns the bookmark backup and the backup directory.
+// The directory changes when firefox creates a new backup
+func (b *firefoxBookmark) Files() []string {
+	return []string{b.bookmarkPath, filepath.Dir(b.bookmarkPath)}
+}
+
 // convertToBookmarks parse a entry and children of the entry
 func (entry *firefoxBookmarkEntry) convertToBookmarks(folder string) (bookmarks Bookmarks) {
 	// firefoxBookmarkEntry.TypeCode
@@ -86,7 +103,7 @@ func (entry *firefoxBookmarkEntry) convertToBookmarks(folder string) (bookmarks
 
 	switch entry.TypeCode {
 	case typeFolder:
-		if entry.Children == nil {
+		if entry.Children == nil || entry.GUID == firefoxTagsRootGUID {
 			// if node has no entry, stop recursive
 			return
 		}
@@ -110,6 +127,13 @@ func (entry *firefoxBookmarkEntry) convertToBookmarks(folder string) (bookmarks
 			Title:          entry.Title,
 			URI:            entry.URI,
 			Domain:         u.Host,
+			ID:             entry.GUID,
+			Added:          convertFirefoxTime(entry.DateAdded),
+			Modified:       convertFirefoxTime(entry.LastModified),
+			Tags:           splitTags(entry.Tags),
+			Keyword:        entry.Keyword,
+			Description:    entry.description(),
+			Index:          entry.Index,
 		}
 		bookmarks = append(bookmarks, b)
 	}
@@ -117,12 +141,22 @@ func (entry *firefoxBookmarkEntry) convertToBookmarks(folder string) (bookmarks
 	return
 }
 
+// description returns a description of the bookmark saved as an annotation
+func (entry *firefoxBookmarkEntry) description() string {
+	for _, anno := range entry.Annos {
+		if anno.Name == firefoxDescriptionAnno {
+			return anno.Value
+		}
+	}
+	return ""
+}
+
 // GetFirefoxBookmarkFile returns a firefox bookmark filepath in bookmark-backups direcotory
 // e.g.) GetFirefoxBookmarkFile(
 //	 os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome"),
 //	"default")
 func GetFirefoxBookmarkFile(profileAbsPath, profileName string) (string, error) {
-	profileDirName, err := searchSuffixDir(profileAbsPath, profileName)
+	profileDirName, err := searchProfileDir(profileAbsPath, profileName, FirefoxProfileList)
 	if err != nil {
 		return "", err
 	}
@@ -138,3 +172,8 @@ func GetFirefoxBookmarkFile(profileAbsPath, profileName string) (string, error)
 
 	return bookmarkFile, nil
 }
+
+// FirefoxProfiles returns directory names of firefox profiles which have bookmarks
+func FirefoxProfiles(profilePath string) ([]string, error) {
+	return searchProfileDirs(profilePath, "places.sqlite", "bookmarkbackups")
+}
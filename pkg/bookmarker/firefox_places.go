@@ -0,0 +1,219 @@
+package bookmarker
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// firefoxPlacesBookmark reads bookmarks from moz_bookmarks and moz_places tables of places.sqlite
+type firefoxPlacesBookmark struct {
+	bookmarkRoot firefoxBookmarkRoot
+	placesPath   string
+}
+
+// NewFirefoxPlaces returns a new firefox instance to get bookmarks from places.sqlite
+func NewFirefoxPlaces(path string) Bookmarker {
+	return &firefoxPlacesBookmark{
+		placesPath: path,
+	}
+}
+
+// Bookmarks load firefox bookmark entries and return general bookmark structure
+func (b *firefoxPlacesBookmark) Bookmarks() (bookmarks Bookmarks, err error) {
+	if err = b.load(); err != nil {
+		return
+	}
+
+	return b.bookmarkRoot.root.convertToBookmarks("/"), nil
+}
+
+// load builds the same tree as .jsonlz4 from a snapshot of places.sqlite
+func (b *firefoxPlacesBookmark) load() error {
+	db, closeDB, err := openSQLiteSnapshot(b.placesPath)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	// Note: keywords belong to urls rather than bookmarks
+	const query = `
+SELECT b.id, b.type, b.parent, b.position, IFNULL(b.title, ''), IFNULL(b.guid, ''),
+	IFNULL(b.dateAdded, 0), IFNULL(b.lastModified, 0), IFNULL(p.url, ''), IFNULL(b.fk, 0),
+	IFNULL((SELECT k.keyword FROM moz_keywords AS k WHERE k.place_id = b.fk ORDER BY k.id LIMIT 1), '')
+FROM moz_bookmarks AS b
+LEFT JOIN moz_places AS p ON b.fk = p.id
+ORDER BY b.parent, b.position`
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[int]*firefoxBookmarkEntry)
+	children := make(map[int][]*firefoxBookmarkEntry)
+	var root *firefoxBookmarkEntry
+	for rows.Next() {
+		var parent int
+		e := new(firefoxBookmarkEntry)
+		if err := rows.Scan(&e.ID, &e.TypeCode, &parent, &e.Index, &e.Title, &e.GUID,
+			&e.DateAdded, &e.LastModified, &e.URI, &e.placeID, &e.Keyword); err != nil {
+			return fmt.Errorf("failed to scan bookmarks: %w", err)
+		}
+		entries[e.ID] = e
+		if parent == 0 {
+			root = e
+			continue
+		}
+		children[parent] = append(children[parent], e)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	if root == nil {
+		return errors.New("root folder is not found in places.sqlite")
+	}
+
+	// rows are sorted by position, so children keep the order of the browser
+	for id, c := range children {
+		if e, ok := entries[id]; ok {
+			e.Children = c
+		}
+	}
+	setFirefoxTags(entries)
+	b.bookmarkRoot.root = *root
+	return nil
+}
+
+// setFirefoxTags sets tags to bookmarks like bookmark backups.
+// places.sqlite saves a tag as a folder under the tags root which has bookmarks of the tagged urls
+func setFirefoxTags(entries map[int]*firefoxBookmarkEntry) {
+	tags := make(map[int][]string)
+	for _, e := range entries {
+		if e.GUID != firefoxTagsRootGUID {
+			continue
+		}
+		for _, tag := range e.Children {
+			for _, c := range tag.Children {
+				tags[c.placeID] = append(tags[c.placeID], tag.Title)
+			}
+		}
+	}
+	if len(tags) == 0 {
+		return
+	}
+
+	for _, e := range entries {
+		if e.URI != "" && e.placeID != 0 {
+			e.Tags = strings.Join(tags[e.placeID], ",")
+		}
+	}
+}
+
+// Files returns places.sqlite and the write-ahead log which has recent changes
+func (b *firefoxPlacesBookmark) Files() []string {
+	return []string{b.placesPath, b.placesPath + "-wal"}
+}
+
+// volatileFiles returns places.sqlite and the write-ahead log as firefox writes them on every visit.
+// Note: the database is locked while firefox is running, so bookmark changes can not be read without copying it
+func (b *firefoxPlacesBookmark) volatileFiles() []string {
+	return b.Files()
+}
+
+// Visits returns visit statistics of urls in moz_places
+func (b *firefoxPlacesBookmark) Visits() (map[string]*Visit, error) {
+	db, closeDB, err := openSQLiteSnapshot(b.placesPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	const query = `
+SELECT url, visit_count, IFNULL(last_visit_date, 0), IFNULL(frecency, 0)
+FROM moz_places
+WHERE visit_count > 0`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query visits: %w", err)
+	}
+	defer rows.Close()
+
+	visits := make(map[string]*Visit)
+	for rows.Next() {
+		var url string
+		var lastVisitDate int64
+		v := new(Visit)
+		if err := rows.Scan(&url, &v.Count, &lastVisitDate, &v.Frecency); err != nil {
+			return nil, fmt.Errorf("failed to scan visits: %w", err)
+		}
+		v.LastVisited = convertFirefoxTime(lastVisitDate)
+		visits[url] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read visits: %w", err)
+	}
+	return visits, nil
+}
+
+// GetFirefoxPlacesFile returns a filepath of firefox places.sqlite which has live bookmarks
+// e.g.) GetFirefoxPlacesFile(
+//
+//	os.ExpandEnv("${HOME}/Library/Application Support/Firefox/Profiles"),
+//	"default")
+func GetFirefoxPlacesFile(profileAbsPath, profileName string) (string, error) {
+	profileDirName, err := searchProfileDir(profileAbsPath, profileName, FirefoxProfileList)
+	if err != nil {
+		return "", err
+	}
+
+	placesFile := filepath.Join(profileAbsPath, profileDirName, "places.sqlite")
+	if err := hasReadCapability(placesFile); err != nil {
+		return "", fmt.Errorf("firefox error: %w", err)
+	}
+
+	return placesFile, nil
+}
+
+// firefoxFallbackBookmark reads places.sqlite first and falls back to a bookmark backup
+// when the database can not be read
+type firefoxFallbackBookmark struct {
+	places Bookmarker
+	backup Bookmarker
+}
+
+// Bookmarks return bookmarks of places.sqlite or bookmark backup
+func (b *firefoxFallbackBookmark) Bookmarks() (Bookmarks, error) {
+	if b.places != nil {
+		bookmarks, err := b.places.Bookmarks()
+		if err == nil || b.backup == nil {
+			return bookmarks, err
+		}
+	}
+
+	return b.backup.Bookmarks()
+}
+
+// Visits returns visit statistics of places.sqlite. Bookmark backups have no history
+func (b *firefoxFallbackBookmark) Visits() (map[string]*Visit, error) {
+	vs, ok := b.places.(VisitSource)
+	if !ok {
+		return nil, errors.New("firefox error: places.sqlite is not available")
+	}
+	return vs.Visits()
+}
+
+// Files returns files of places.sqlite and bookmark backup
+func (b *firefoxFallbackBookmark) Files() []string {
+	return appendFiles(appendFiles(nil, b.places), b.backup)
+}
+
+// volatileFiles returns volatile files of places.sqlite. bookmark backups are not written on every visit
+func (b *firefoxFallbackBookmark) volatileFiles() []string {
+	if vs, ok := b.places.(volatileFileSource); ok {
+		return vs.volatileFiles()
+	}
+	return nil
+}
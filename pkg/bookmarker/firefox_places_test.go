@@ -0,0 +1,210 @@
+package bookmarker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testFirefoxPlacesSchema is a subset of places.sqlite schema
+const testFirefoxPlacesSchema = `
+CREATE TABLE moz_places (
+	id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR,
+	visit_count INTEGER DEFAULT 0, frecency INTEGER DEFAULT -1, last_visit_date INTEGER, guid TEXT, hidden INTEGER DEFAULT 0
+);
+CREATE TABLE moz_bookmarks (
+	id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER DEFAULT NULL, parent INTEGER, position INTEGER,
+	title LONGVARCHAR, keyword_id INTEGER, dateAdded INTEGER, lastModified INTEGER, guid TEXT
+);
+CREATE TABLE moz_keywords (id INTEGER PRIMARY KEY AUTOINCREMENT, keyword TEXT UNIQUE, place_id INTEGER, post_data TEXT);`
+
+func TestFirefoxPlacesBookmarks(t *testing.T) {
+	tests := []struct {
+		description string
+		placesPath  string
+		want        Bookmarks
+		expectErr   bool
+	}{
+		{
+			description: "valid places file",
+			placesPath:  createTestFirefoxPlaces(t),
+			want:        testFirefoxBookmarks,
+		},
+		{
+			description: "invalid places file",
+			placesPath:  "test",
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			b := NewFirefoxPlaces(tt.placesPath)
+
+			bookmarks, err := b.Bookmarks()
+			if tt.expectErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error got: %+v", err)
+			}
+
+			diff := DiffBookmark(bookmarks, tt.want)
+			if !tt.expectErr && diff != "" {
+				t.Errorf("+want -got\n%+v", diff)
+			}
+		})
+	}
+}
+
+func TestFirefoxFallbackBookmarks(t *testing.T) {
+	setupFirefox(t)
+	tests := []struct {
+		description string
+		bookmarker  Bookmarker
+		want        Bookmarks
+		expectErr   bool
+	}{
+		{
+			description: "places.sqlite is available",
+			bookmarker: &firefoxFallbackBookmark{
+				places: NewFirefoxPlaces(createTestFirefoxPlaces(t)),
+				backup: NewFirefox("test"),
+			},
+			want: testFirefoxBookmarks,
+		},
+		{
+			description: "fallback to bookmark backup if places.sqlite is broken",
+			bookmarker: &firefoxFallbackBookmark{
+				places: NewFirefoxPlaces(testFirefoxBookmarkJSONFile),
+				backup: NewFirefox(testFirefoxBookmarkJsonlz4File),
+			},
+			want: testFirefoxBookmarks,
+		},
+		{
+			description: "no backup",
+			bookmarker: &firefoxFallbackBookmark{
+				places: NewFirefoxPlaces("test"),
+			},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			bookmarks, err := tt.bookmarker.Bookmarks()
+			if tt.expectErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error got: %+v", err)
+			}
+
+			diff := DiffBookmark(bookmarks, tt.want)
+			if !tt.expectErr && diff != "" {
+				t.Errorf("+want -got\n%+v", diff)
+			}
+		})
+	}
+}
+
+// createTestFirefoxPlaces creates places.sqlite from the test json file and returns the path
+func createTestFirefoxPlaces(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(testFirefoxBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := new(firefoxBookmarkEntry)
+	if err := json.Unmarshal(data, root); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "places.sqlite")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(testFirefoxPlacesSchema); err != nil {
+		t.Fatal(err)
+	}
+	if err := insertTestFirefoxPlaces(db, root, 0); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func insertTestFirefoxPlaces(db *sql.DB, entry *firefoxBookmarkEntry, parent int) error {
+	var fk interface{}
+	if entry.URI != "" {
+		res, err := db.Exec(`INSERT INTO moz_places (url, title) VALUES (?, ?)`, entry.URI, entry.Title)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		fk = id
+	}
+
+	_, err := db.Exec(
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, entry.TypeCode, fk, parent, entry.Index, entry.Title, entry.DateAdded, entry.LastModified, entry.GUID)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range entry.Children {
+		if err := insertTestFirefoxPlaces(db, c, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestFirefoxPlacesTagsAndKeywords(t *testing.T) {
+	placesPath := createTestFirefoxPlaces(t)
+	db := openTestDB(t, placesPath)
+	var githubID int
+	if err := db.QueryRow(`SELECT id FROM moz_places WHERE url = ?`, "https://github.com/").Scan(&githubID); err != nil {
+		t.Fatal(err)
+	}
+	// the tags root has tag folders which have bookmarks of tagged urls
+	if _, err := db.Exec(`INSERT INTO moz_bookmarks (id, type, fk, parent, position, title, guid) VALUES
+		(1000, 2, NULL, 1, 5, 'tags', ?), (1001, 2, NULL, 1000, 0, 'dev', 'tagdev'), (1002, 1, ?, 1001, 0, NULL, 'tagged1'),
+		(1003, 2, NULL, 1000, 1, 'git', 'taggit'), (1004, 1, ?, 1003, 0, NULL, 'tagged2')`,
+		firefoxTagsRootGUID, githubID, githubID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO moz_keywords (keyword, place_id) VALUES ('gh', ?)`, githubID); err != nil {
+		t.Fatal(err)
+	}
+
+	bookmarks, err := NewFirefoxPlaces(placesPath).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bookmarks) != len(testFirefoxBookmarks) {
+		t.Errorf("tag folders should not be searched, got %d bookmarks", len(bookmarks))
+	}
+	for _, b := range bookmarks {
+		if b.URI != "https://github.com/" {
+			if len(b.Tags) != 0 || b.Keyword != "" {
+				t.Errorf("unexpected tags %v and keyword %s of %s", b.Tags, b.Keyword, b.URI)
+			}
+			continue
+		}
+		if diff := cmp.Diff([]string{"dev", "git"}, b.Tags); diff != "" {
+			t.Errorf("-want +got\n%s", diff)
+		}
+		if b.Keyword != "gh" {
+			t.Errorf("want keyword gh, got %s", b.Keyword)
+		}
+	}
+}
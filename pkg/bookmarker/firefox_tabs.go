@@ -0,0 +1,171 @@
+package bookmarker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/konoui/alfred-bookmarks/pkg/mozlz4"
+)
+
+// FirefoxTabs is a name of open tabs of firefox
+const FirefoxTabs bookmarkerName = "firefox_tabs"
+
+// Tab is a position of an open tab
+type Tab struct {
+	// Window and Index are 1-based positions of the window and the tab in the window
+	Window int
+	Index  int
+	// Group is a name of the tab group. empty if the tab is not in a group
+	Group string
+}
+
+// firefoxSession is a session of decompressed recovery.jsonlz4
+type firefoxSession struct {
+	Windows []*firefoxSessionWindow `json:"windows"`
+}
+
+type firefoxSessionWindow struct {
+	Tabs   []*firefoxSessionTab `json:"tabs"`
+	Groups []*firefoxTabGroup   `json:"groups"`
+}
+
+type firefoxSessionTab struct {
+	// Entries are the navigation history of the tab
+	Entries []*firefoxSessionEntry `json:"entries"`
+	// Index is a 1-based index of the current entry
+	Index   int    `json:"index"`
+	Hidden  bool   `json:"hidden"`
+	GroupID string `json:"groupId,omitempty"`
+	// LastAccessed is milliseconds since 1970-01-01
+	LastAccessed int64 `json:"lastAccessed"`
+}
+
+type firefoxSessionEntry struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type firefoxTabGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// firefoxTabsBookmark reads open tabs from a session file
+type firefoxTabsBookmark struct {
+	sessionPath string
+}
+
+// NewFirefoxTabs returns a bookmarker of open tabs in sessionstore-backups/recovery.jsonlz4
+func NewFirefoxTabs(path string) Bookmarker {
+	return &firefoxTabsBookmark{
+		sessionPath: path,
+	}
+}
+
+// Bookmarks returns open tabs. A folder of a tab is the window and the tab group.
+// BookmarkerName is left empty and the manager fills in FirefoxTabs
+func (b *firefoxTabsBookmark) Bookmarks() (Bookmarks, error) {
+	f, err := os.Open(b.sessionPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := mozlz4.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	session := new(firefoxSession)
+	if err := json.NewDecoder(r).Decode(session); err != nil {
+		return nil, fmt.Errorf("failed to decode the session: %w", err)
+	}
+	return session.convertToBookmarks(), nil
+}
+
+// Files returns the session file
+func (b *firefoxTabsBookmark) Files() []string {
+	return []string{b.sessionPath}
+}
+
+func (s *firefoxSession) convertToBookmarks() (bookmarks Bookmarks) {
+	for i, w := range s.Windows {
+		groups := make(map[string]string)
+		for _, g := range w.Groups {
+			groups[g.ID] = g.Name
+		}
+
+		for j, tab := range w.Tabs {
+			if tab.Hidden || tab.Index < 1 || tab.Index > len(tab.Entries) {
+				continue
+			}
+			entry := tab.Entries[tab.Index-1]
+			u, err := parseURL(entry.URL)
+			// Note: internal pages like about:newtab have no host
+			if err != nil {
+				continue
+			}
+
+			t := &Tab{Window: i + 1, Index: j + 1, Group: groups[tab.GroupID]}
+			folder := "/Window " + strconv.Itoa(t.Window)
+			if t.Group != "" {
+				folder += "/" + t.Group
+			}
+			title := entry.Title
+			if title == "" {
+				title = entry.URL
+			}
+			var lastAccessed time.Time
+			if tab.LastAccessed > 0 {
+				lastAccessed = time.UnixMilli(tab.LastAccessed).UTC()
+			}
+			bookmarks = append(bookmarks, &Bookmark{
+				Folder:      folder,
+				Title:       title,
+				Domain:      u.Host,
+				URI:         entry.URL,
+				LastVisited: lastAccessed,
+				Tab:         t,
+			})
+		}
+	}
+	return
+}
+
+// WithFirefoxTabs if called, search open tabs of firefox profiles
+func WithFirefoxTabs(profilePath string, profileNames ...string) Option {
+	return func(m *Manager) error {
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, FirefoxProfiles, FirefoxProfileList)
+		if err != nil {
+			m.addError(FirefoxTabs, "", err)
+			return nil
+		}
+
+		for _, profileDirName := range profileDirNames {
+			path, err := GetFirefoxSessionFile(profilePath, profileDirName)
+			if err != nil {
+				m.addError(FirefoxTabs, profileDirName, err)
+				continue
+			}
+			m.add(FirefoxTabs, profileDirName, NewFirefoxTabs(path))
+		}
+		return nil
+	}
+}
+
+// GetFirefoxSessionFile returns a filepath of the session which firefox saves while running
+func GetFirefoxSessionFile(profileAbsPath, profileName string) (string, error) {
+	profileDirName, err := searchProfileDir(profileAbsPath, profileName, FirefoxProfileList)
+	if err != nil {
+		return "", err
+	}
+
+	sessionFile := filepath.Join(profileAbsPath, profileDirName, "sessionstore-backups", "recovery.jsonlz4")
+	if err := hasReadCapability(sessionFile); err != nil {
+		return "", fmt.Errorf("firefox error: %w", err)
+	}
+	return sessionFile, nil
+}
@@ -0,0 +1,132 @@
+package bookmarker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testFirefoxSessionJSONFile = filepath.Join(testdataPath, "test-firefox-session.json")
+
+func TestFirefoxTabsBookmarks(t *testing.T) {
+	tests := []struct {
+		description string
+		sessionPath string
+		want        Bookmarks
+		expectErr   bool
+	}{
+		{
+			description: "open tabs of windows and tab groups",
+			sessionPath: createTestFirefoxSession(t),
+			want: Bookmarks{
+				{
+					Folder:         "/Window 1",
+					Title:          "GitHub",
+					Domain:         "github.com",
+					URI:            "https://github.com/",
+					LastVisited:    time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+					Tab:            &Tab{Window: 1, Index: 1},
+				},
+				{
+					Folder:         "/Window 1/infra",
+					Title:          "Dashboard",
+					Domain:         "grafana.example.com",
+					URI:            "https://grafana.example.com/d/abc",
+					LastVisited:    time.Date(2023, 6, 1, 1, 0, 0, 0, time.UTC),
+					Tab:            &Tab{Window: 1, Index: 3, Group: "infra"},
+				},
+				{
+					Folder:         "/Window 2",
+					Title:          "https://go.dev/",
+					Domain:         "go.dev",
+					URI:            "https://go.dev/",
+					Tab:            &Tab{Window: 2, Index: 2},
+				},
+			},
+		},
+		{
+			description: "session file is not compressed",
+			sessionPath: testFirefoxSessionJSONFile,
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := NewFirefoxTabs(tt.sessionPath).Bookmarks()
+			if tt.expectErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error got: %+v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBookmarks_MergeByURIKeepsTabs(t *testing.T) {
+	b := Bookmarks{
+		{BookmarkerName: Firefox, URI: "https://github.com/"},
+		{BookmarkerName: FirefoxTabs, URI: "https://github.com/", Tab: &Tab{Window: 1, Index: 1}},
+		{BookmarkerName: FirefoxTabs, URI: "https://github.com/", Tab: &Tab{Window: 1, Index: 2}},
+		{BookmarkerName: Chrome, URI: "https://github.com/", History: true},
+	}
+	got := b.withoutBookmarked(nil).mergeByURI(nil, nil)
+	if len(got) != 3 || got[0] != b[0] || got[1] != b[1] || got[2] != b[2] {
+		t.Errorf("open tabs should be kept %v", got)
+	}
+}
+
+func TestWithFirefoxTabs(t *testing.T) {
+	profilePath := t.TempDir()
+	dir := filepath.Join(profilePath, "abcdefgh.default", "sessionstore-backups")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(createTestFirefoxSession(t), filepath.Join(dir, "recovery.jsonlz4")); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := New(WithFirefoxTabs(profilePath, "abcdefgh.default"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookmarks, err := m.Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bookmarks {
+		if b.BookmarkerName != FirefoxTabs || b.Profile != "abcdefgh.default" || b.Tab == nil {
+			t.Errorf("unexpected tab %+v", b)
+		}
+	}
+	if len(bookmarks) != 3 {
+		t.Errorf("want 3 tabs, got %d", len(bookmarks))
+	}
+}
+
+// createTestFirefoxSession creates recovery.jsonlz4 from the test json file and returns the path
+func createTestFirefoxSession(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(testFirefoxSessionJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "recovery.jsonlz4")
+	w, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := compress(strings.NewReader(string(data)), w, len(data)); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pierrec/lz4"
 )
@@ -24,6 +25,10 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Google",
 		Domain:         "www.google.com",
 		URI:            "https://www.google.com/",
+		ID:             "PiI2rUY2fB4G",
+		Added:          time.Date(2019, 12, 15, 8, 37, 2, 214000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 37, 8, 89000000, time.UTC),
+		Index:          6,
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -31,6 +36,10 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "GitHub",
 		Domain:         "github.com",
 		URI:            "https://github.com/",
+		ID:             "TMhIbbqmZuQ5",
+		Added:          time.Date(2019, 12, 15, 8, 37, 28, 142000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 37, 52, 544000000, time.UTC),
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -38,6 +47,9 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Stack Overflow",
 		Domain:         "stackoverflow.com",
 		URI:            "https://stackoverflow.com/",
+		ID:             "7uRvk8KKjKPm",
+		Added:          time.Date(2019, 12, 15, 8, 38, 10, 788000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 38, 37, 274000000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -45,6 +57,10 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Amazon Web Services",
 		Domain:         "aws.amazon.com",
 		URI:            "https://aws.amazon.com/?nc1=h_ls",
+		ID:             "irL3kAUwsO1R",
+		Added:          time.Date(2019, 12, 15, 8, 39, 6, 144000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 39, 13, 967000000, time.UTC),
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -52,6 +68,9 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Yahoo",
 		Domain:         "www.yahoo.com",
 		URI:            "https://www.yahoo.com/",
+		ID:             "HkEHFDU8y1nx",
+		Added:          time.Date(2019, 12, 15, 8, 40, 4, 422000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 40, 11, 441000000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -59,6 +78,9 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Facebook",
 		Domain:         "www.facebook.com",
 		URI:            "https://www.facebook.com/",
+		ID:             "YstPQ09r-5rm",
+		Added:          time.Date(2019, 12, 15, 8, 41, 12, 73000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 41, 38, 564000000, time.UTC),
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -66,6 +88,10 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Twitter",
 		Domain:         "twitter.com",
 		URI:            "https://twitter.com/login",
+		ID:             "K1ONXeTKyu5A",
+		Added:          time.Date(2019, 12, 15, 8, 41, 55, 460000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 42, 7, 618000000, time.UTC),
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Firefox,
@@ -73,6 +99,9 @@ var testFirefoxBookmarks = Bookmarks{
 		Title:          "Amazon.com",
 		Domain:         "www.amazon.com",
 		URI:            "https://www.amazon.com/",
+		ID:             "oCtRqDMBnaFJ",
+		Added:          time.Date(2019, 12, 15, 8, 42, 21, 996000000, time.UTC),
+		Modified:       time.Date(2019, 12, 15, 8, 42, 34, 960000000, time.UTC),
 	},
 }
 
@@ -118,6 +147,37 @@ func TestFirefoxBookmarks(t *testing.T) {
 	}
 }
 
+func TestFirefoxBookmarksTagsRoot(t *testing.T) {
+	// bookmark backups have tags and keywords in bookmarks, and the tags root has tag folders
+	root := &firefoxBookmarkEntry{
+		TypeCode: 2,
+		Children: []*firefoxBookmarkEntry{
+			{
+				TypeCode: 2, Title: "Bookmark Menu",
+				Children: []*firefoxBookmarkEntry{
+					{TypeCode: 1, Title: "GitHub", URI: "https://github.com/", Tags: "dev,git", Keyword: "gh"},
+				},
+			},
+			{
+				TypeCode: 2, Title: "tags", GUID: firefoxTagsRootGUID,
+				Children: []*firefoxBookmarkEntry{
+					{
+						TypeCode: 2, Title: "dev",
+						Children: []*firefoxBookmarkEntry{{TypeCode: 1, URI: "https://github.com/"}},
+					},
+				},
+			},
+		},
+	}
+	bookmarks := root.convertToBookmarks("/")
+	if len(bookmarks) != 1 {
+		t.Fatalf("tag folders should not be searched, got %d bookmarks", len(bookmarks))
+	}
+	if b := bookmarks[0]; b.Folder != "/Bookmark Menu" || b.Keyword != "gh" || strings.Join(b.Tags, ",") != "dev,git" {
+		t.Errorf("unexpected bookmark %+v", b)
+	}
+}
+
 func setupFirefox(t *testing.T) {
 	t.Helper()
 	if err := createTestFirefoxJsonlz4(); err != nil {
@@ -6,11 +6,16 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
 
 func parseURL(s string) (u *url.URL, err error) {
+	if isTemplate(s) {
+		// Note: placeholders of url templates are invalid escapes
+		s = strings.NewReplacer("%s", "s", "%S", "s").Replace(s)
+	}
 	u, err = url.Parse(s)
 	// Ignore invalid URLs
 	if err != nil {
@@ -22,6 +27,47 @@ func parseURL(s string) (u *url.URL, err error) {
 	return
 }
 
+// chromeEpochOffset is microseconds between 1601-01-01 and 1970-01-01
+const chromeEpochOffset = 11644473600000000
+
+// convertChromeTime converts microseconds since 1601-01-01 UTC into time.Time.
+// Chrome saves timestamps as string in bookmark files
+func convertChromeTime(s string) time.Time {
+	us, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || us <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(us - chromeEpochOffset).UTC()
+}
+
+// convertUnixTime converts seconds since 1970-01-01 UTC into time.Time
+func convertUnixTime(s string) time.Time {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+// convertFirefoxTime converts PRTime which is microseconds since 1970-01-01 UTC into time.Time
+func convertFirefoxTime(us int64) time.Time {
+	if us <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(us).UTC()
+}
+
+// splitTags splits comma separated tags and trims spaces. empty tags are ignored
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 func getHomeDir() (string, error) {
 	return os.UserHomeDir()
 }
@@ -76,6 +122,59 @@ func searchSuffixDir(dir, suffix string) (string, error) {
 	return "", fmt.Errorf("not found a directory of suffix (%s) in %s directory", suffix, dir)
 }
 
+// searchProfileDirs returns directory names which have one of files at least
+func searchProfileDirs(dir string, files ...string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(dir, entry.Name(), file)); err == nil {
+				names = append(names, entry.Name())
+				break
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("not found profile directories in %s directory", dir)
+	}
+	return names, nil
+}
+
+// resolveProfileDirNames returns unique directory names of the profiles.
+// If profileNames is empty, all profiles are returned by searching profilePath.
+func resolveProfileDirNames(profilePath string, profileNames []string,
+	all func(string) ([]string, error), list profileLister) ([]string, error) {
+	if len(profileNames) == 0 {
+		return all(profilePath)
+	}
+
+	m := make(map[string]bool)
+	dirNames := make([]string, 0, len(profileNames))
+	for _, profileName := range profileNames {
+		dirName := profileName
+		if profileName != "" {
+			var err error
+			dirName, err = searchProfileDir(profilePath, profileName, list)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !m[dirName] {
+			m[dirName] = true
+			dirNames = append(dirNames, dirName)
+		}
+	}
+	return dirNames, nil
+}
+
 // hasReadCapability return nil if the filepath stats and has read permission
 func hasReadCapability(path string) error {
 	const (
@@ -0,0 +1,317 @@
+package bookmarker
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// safariEpochOffset is seconds between 1970-01-01 and 2001-01-01 which Safari history counts from
+const safariEpochOffset = 978307200
+
+// HistoryLimit caps entries of browser history
+type HistoryLimit struct {
+	// MaxAge is the age of the oldest visit to read. zero means no limit
+	MaxAge time.Duration
+	// MaxEntries is the number of recently visited urls to read per profile. zero means no limit
+	MaxEntries int
+}
+
+// defaultHistoryLimit reads visits in the last 30 days at most 500 urls
+var defaultHistoryLimit = HistoryLimit{
+	MaxAge:     30 * 24 * time.Hour,
+	MaxEntries: 500,
+}
+
+// since returns the oldest visit time to read
+func (l HistoryLimit) since(now time.Time) time.Time {
+	if l.MaxAge <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-l.MaxAge)
+}
+
+// limit returns a value for LIMIT clause. -1 means no limit in sqlite
+func (l HistoryLimit) limit() int {
+	if l.MaxEntries <= 0 {
+		return -1
+	}
+	return l.MaxEntries
+}
+
+// historyBookmarker is a bookmarker of browser history. Manager passes the limit before loading
+type historyBookmarker interface {
+	setLimit(l HistoryLimit)
+}
+
+// historyBookmark reads visited urls from a history database of a browser as bookmarks
+type historyBookmark struct {
+	name  bookmarkerName
+	path  string
+	query string
+	// scan converts a row of the query into a bookmark
+	scan  func(scan func(dest ...interface{}) error) (*Bookmark, error)
+	since func(t time.Time) interface{}
+	lim   HistoryLimit
+}
+
+// NewFirefoxHistory returns a bookmarker of visited urls in places.sqlite
+func NewFirefoxHistory(path string) Bookmarker {
+	return &historyBookmark{
+		name: Firefox,
+		path: path,
+		query: `
+SELECT url, IFNULL(title, ''), visit_count, last_visit_date, IFNULL(frecency, 0)
+FROM moz_places
+WHERE hidden = 0 AND visit_count > 0 AND last_visit_date >= ?
+ORDER BY last_visit_date DESC
+LIMIT ?`,
+		scan: func(scan func(dest ...interface{}) error) (*Bookmark, error) {
+			var lastVisitDate int64
+			b := new(Bookmark)
+			if err := scan(&b.URI, &b.Title, &b.VisitCount, &lastVisitDate, &b.Frecency); err != nil {
+				return nil, err
+			}
+			b.LastVisited = convertFirefoxTime(lastVisitDate)
+			return b, nil
+		},
+		since: func(t time.Time) interface{} {
+			if t.IsZero() {
+				return 0
+			}
+			return t.UnixMicro()
+		},
+		lim: defaultHistoryLimit,
+	}
+}
+
+// NewChromeHistory returns a bookmarker of visited urls in History of chrome or chromium-based browsers
+func NewChromeHistory(path string) Bookmarker {
+	return newChromiumHistory(Chrome, path)
+}
+
+func newChromiumHistory(name bookmarkerName, path string) *historyBookmark {
+	return &historyBookmark{
+		name: name,
+		path: path,
+		query: `
+SELECT url, title, visit_count, last_visit_time
+FROM urls
+WHERE hidden = 0 AND visit_count > 0 AND last_visit_time >= ?
+ORDER BY last_visit_time DESC
+LIMIT ?`,
+		scan: func(scan func(dest ...interface{}) error) (*Bookmark, error) {
+			var lastVisitTime string
+			b := new(Bookmark)
+			if err := scan(&b.URI, &b.Title, &b.VisitCount, &lastVisitTime); err != nil {
+				return nil, err
+			}
+			b.LastVisited = convertChromeTime(lastVisitTime)
+			return b, nil
+		},
+		since: func(t time.Time) interface{} {
+			if t.IsZero() {
+				return 0
+			}
+			return t.UnixMicro() + chromeEpochOffset
+		},
+		lim: defaultHistoryLimit,
+	}
+}
+
+// NewSafariHistory returns a bookmarker of visited urls in History.db of safari
+func NewSafariHistory(path string) Bookmarker {
+	return &historyBookmark{
+		name: Safari,
+		path: path,
+		// Note: sqlite returns the title of the latest visit with MAX()
+		query: `
+SELECT i.url, IFNULL(v.title, ''), i.visit_count, MAX(v.visit_time)
+FROM history_items AS i
+JOIN history_visits AS v ON v.history_item = i.id
+GROUP BY i.id
+HAVING MAX(v.visit_time) >= ?
+ORDER BY MAX(v.visit_time) DESC
+LIMIT ?`,
+		scan: func(scan func(dest ...interface{}) error) (*Bookmark, error) {
+			var visitTime float64
+			b := new(Bookmark)
+			if err := scan(&b.URI, &b.Title, &b.VisitCount, &visitTime); err != nil {
+				return nil, err
+			}
+			b.LastVisited = convertSafariTime(visitTime)
+			return b, nil
+		},
+		since: func(t time.Time) interface{} {
+			if t.IsZero() {
+				return float64(-safariEpochOffset)
+			}
+			return float64(t.Unix() - safariEpochOffset)
+		},
+		lim: defaultHistoryLimit,
+	}
+}
+
+func (b *historyBookmark) setLimit(l HistoryLimit) {
+	b.lim = l
+}
+
+// Bookmarks returns recently visited urls as bookmarks which are flagged as history
+func (b *historyBookmark) Bookmarks() (Bookmarks, error) {
+	db, closeDB, err := openSQLiteSnapshot(b.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	rows, err := db.Query(b.query, b.since(b.lim.since(time.Now())), b.lim.limit())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	var bookmarks Bookmarks
+	for rows.Next() {
+		bookmark, err := b.scan(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+		u, err := parseURL(bookmark.URI)
+		if err != nil {
+			continue
+		}
+		bookmark.BookmarkerName = b.name
+		bookmark.Domain = u.Host
+		bookmark.History = true
+		if bookmark.Title == "" {
+			bookmark.Title = bookmark.URI
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return bookmarks, nil
+}
+
+// Files returns the history database and the write-ahead log
+func (b *historyBookmark) Files() []string {
+	return []string{b.path, b.path + "-wal"}
+}
+
+// volatileFiles returns the history database and the write-ahead log as browsers write them on every visit
+func (b *historyBookmark) volatileFiles() []string {
+	return b.Files()
+}
+
+// WithFirefoxHistory if called, search visited urls of firefox profiles
+func WithFirefoxHistory(profilePath string, profileNames ...string) Option {
+	return func(m *Manager) error {
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, FirefoxProfiles, FirefoxProfileList)
+		if err != nil {
+			m.addError(Firefox, "", err)
+			return nil
+		}
+
+		for _, profileDirName := range profileDirNames {
+			path, err := GetFirefoxPlacesFile(profilePath, profileDirName)
+			if err != nil {
+				m.addError(Firefox, profileDirName, err)
+				continue
+			}
+			m.add(Firefox, profileDirName, NewFirefoxHistory(path))
+		}
+		return nil
+	}
+}
+
+// WithChromiumHistory if called, search visited urls of profiles of the chrome or chromium-based browser
+func WithChromiumHistory(browser string, profilePath string, profileNames ...string) Option {
+	return func(m *Manager) error {
+		name := bookmarkerName(browser)
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, ChromiumProfiles, ChromiumProfileList)
+		if err != nil {
+			m.addError(name, "", err)
+			return nil
+		}
+
+		for _, profileDirName := range profileDirNames {
+			path := filepath.Join(profilePath, profileDirName, "History")
+			if err := hasReadCapability(path); err != nil {
+				m.addError(name, profileDirName, fmt.Errorf("%s error: %w", name, err))
+				continue
+			}
+			m.add(name, profileDirName, newChromiumHistory(name, path))
+		}
+		return nil
+	}
+}
+
+// WithSafariHistory if called, search visited urls of safari
+func WithSafariHistory() Option {
+	return func(m *Manager) error {
+		bookmarkFile, err := GetSafariBookmarkFile()
+		if err != nil {
+			m.addError(Safari, "", err)
+			return nil
+		}
+		path := filepath.Join(filepath.Dir(bookmarkFile), "History.db")
+		if err := hasReadCapability(path); err != nil {
+			m.addError(Safari, "", fmt.Errorf("safari error: %w", err))
+			return nil
+		}
+
+		m.add(Safari, "", NewSafariHistory(path))
+		return nil
+	}
+}
+
+// WithHistoryLimit replaces the default limit of history, which is 30 days and 500 urls per profile
+func WithHistoryLimit(maxAge time.Duration, maxEntries int) Option {
+	return func(m *Manager) error {
+		m.historyLimit = HistoryLimit{MaxAge: maxAge, MaxEntries: maxEntries}
+		return nil
+	}
+}
+
+// withoutBookmarked removes history entries whose urls are bookmarked. open tabs are not bookmarks
+func (b Bookmarks) withoutBookmarked(n *URLNormalizer) Bookmarks {
+	key := func(uri string) string {
+		if n != nil {
+			return n.Normalize(uri)
+		}
+		return uri
+	}
+
+	hasHistory := false
+	bookmarked := make(map[string]bool)
+	for _, e := range b {
+		if e.History {
+			hasHistory = true
+			continue
+		}
+		if e.Tab == nil {
+			bookmarked[key(e.URI)] = true
+		}
+	}
+	if !hasHistory {
+		return b
+	}
+
+	ret := make(Bookmarks, 0, len(b))
+	for _, e := range b {
+		if e.History && bookmarked[key(e.URI)] {
+			continue
+		}
+		ret = append(ret, e)
+	}
+	return ret
+}
+
+// convertSafariTime converts seconds since 2001-01-01 UTC into time.Time
+func convertSafariTime(sec float64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(sec)+safariEpochOffset, 0).UTC()
+}
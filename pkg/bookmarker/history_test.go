@@ -0,0 +1,134 @@
+package bookmarker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHistoryBookmarks(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	recent, old := now.Add(-time.Hour), now.Add(-60*24*time.Hour)
+
+	placesPath := createTestFirefoxPlaces(t)
+	db := openTestDB(t, placesPath)
+	if _, err := db.Exec(`INSERT INTO moz_places (url, title, visit_count, frecency, last_visit_date, hidden) VALUES
+		(?, 'Go', 3, 200, ?, 0), (?, 'Old', 1, 10, ?, 0), (?, 'Hidden', 1, 10, ?, 1)`,
+		"https://go.dev/", recent.UnixMicro(),
+		"https://old.example.com/", old.UnixMicro(),
+		"https://hidden.example.com/", recent.UnixMicro()); err != nil {
+		t.Fatal(err)
+	}
+
+	chromePath := filepath.Join(t.TempDir(), "History")
+	db = openTestDB(t, chromePath)
+	if _, err := db.Exec(`CREATE TABLE urls (id INTEGER PRIMARY KEY, url LONGVARCHAR, title LONGVARCHAR,
+		visit_count INTEGER, last_visit_time INTEGER, hidden INTEGER DEFAULT 0)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO urls (url, title, visit_count, last_visit_time) VALUES (?, 'Go', 2, ?), (?, '', 1, ?)`,
+		"https://go.dev/", recent.UnixMicro()+chromeEpochOffset,
+		"https://pkg.go.dev/", old.UnixMicro()+chromeEpochOffset); err != nil {
+		t.Fatal(err)
+	}
+
+	safariPath := filepath.Join(t.TempDir(), "History.db")
+	db = openTestDB(t, safariPath)
+	if _, err := db.Exec(`
+CREATE TABLE history_items (id INTEGER PRIMARY KEY, url TEXT, visit_count INTEGER);
+CREATE TABLE history_visits (id INTEGER PRIMARY KEY, history_item INTEGER, visit_time REAL, title TEXT);`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO history_items (id, url, visit_count) VALUES (1, ?, 2)`, "https://go.dev/"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO history_visits (history_item, visit_time, title) VALUES (1, ?, 'Old Go'), (1, ?, 'Go')`,
+		float64(old.Unix()-safariEpochOffset), float64(recent.Unix()-safariEpochOffset)); err != nil {
+		t.Fatal(err)
+	}
+
+	goHistory := func(name bookmarkerName, visitCount, frecency int) *Bookmark {
+		return &Bookmark{
+			BookmarkerName: name,
+			Title:          "Go",
+			Domain:         "go.dev",
+			URI:            "https://go.dev/",
+			History:        true,
+			VisitCount:     visitCount,
+			LastVisited:    recent,
+			Frecency:       frecency,
+		}
+	}
+	tests := []struct {
+		name       string
+		bookmarker Bookmarker
+		limit      HistoryLimit
+		want       Bookmarks
+	}{
+		{
+			name:       "firefox history in the last 30 days",
+			bookmarker: NewFirefoxHistory(placesPath),
+			limit:      defaultHistoryLimit,
+			want:       Bookmarks{goHistory(Firefox, 3, 200)},
+		},
+		{
+			name:       "chrome history without limits",
+			bookmarker: NewChromeHistory(chromePath),
+			want: Bookmarks{
+				goHistory(Chrome, 2, 0),
+				{
+					BookmarkerName: Chrome,
+					Title:          "https://pkg.go.dev/",
+					Domain:         "pkg.go.dev",
+					URI:            "https://pkg.go.dev/",
+					History:        true,
+					VisitCount:     1,
+					LastVisited:    old,
+				},
+			},
+		},
+		{
+			name:       "chrome history is capped by the count",
+			bookmarker: NewChromeHistory(chromePath),
+			limit:      HistoryLimit{MaxEntries: 1},
+			want:       Bookmarks{goHistory(Chrome, 2, 0)},
+		},
+		{
+			name:       "safari history has the title of the latest visit",
+			bookmarker: NewSafariHistory(safariPath),
+			limit:      defaultHistoryLimit,
+			want:       Bookmarks{goHistory(Safari, 2, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.bookmarker.(historyBookmarker).setLimit(tt.limit)
+			got, err := tt.bookmarker.Bookmarks()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBookmarks_WithoutBookmarked(t *testing.T) {
+	b := Bookmarks{
+		{BookmarkerName: Chrome, URI: "https://github.com/"},
+		{BookmarkerName: Chrome, URI: "http://www.github.com", History: true},
+		{BookmarkerName: Firefox, URI: "https://go.dev/", History: true},
+	}
+	got := b.withoutBookmarked(NewURLNormalizer())
+	if len(got) != 2 || got[0] != b[0] || got[1] != b[2] {
+		t.Errorf("unexpected bookmarks %v", got)
+	}
+
+	got = b.withoutBookmarked(nil)
+	if len(got) != 3 {
+		t.Errorf("urls should be compared exactly %v", got)
+	}
+}
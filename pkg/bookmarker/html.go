@@ -0,0 +1,158 @@
+package bookmarker
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// htmlBookmark reads a bookmark file of NETSCAPE-Bookmark-file-1 format
+// which browsers and bookmark services export
+type htmlBookmark struct {
+	bookmarkPath string
+}
+
+// NewHTML returns a new html instance to get bookmarks
+func NewHTML(path string) Bookmarker {
+	return &htmlBookmark{
+		bookmarkPath: path,
+	}
+}
+
+// Bookmarks load bookmark entries of the html file and return general bookmark structure
+func (b *htmlBookmark) Bookmarks() (Bookmarks, error) {
+	f, err := os.Open(b.bookmarkPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseBookmarkHTML(f)
+}
+
+// Files returns the bookmark file
+func (b *htmlBookmark) Files() []string {
+	return []string{b.bookmarkPath}
+}
+
+// parseBookmarkHTML parses a bookmark file.
+// `<DT><H3>` is a folder of the next `<DL>` and `<DT><A>` is a bookmark.
+// `<DD>` after `<A>` is a description of the bookmark
+func parseBookmarkHTML(r io.Reader) (bookmarks Bookmarks, err error) {
+	var (
+		z = html.NewTokenizer(r)
+		// folders are names of opened <DL>
+		folders []string
+		// indexes are numbers of entries in opened <DL>
+		indexes []int
+		// folder is a name of the last <H3> which is a folder of the next <DL>
+		folder string
+		// last is the last bookmark which can have a description
+		last *Bookmark
+		// inDD is true while reading text of <DD>
+		inDD bool
+	)
+
+	nextIndex := func() int {
+		if len(indexes) == 0 {
+			return 0
+		}
+		i := indexes[len(indexes)-1]
+		indexes[len(indexes)-1]++
+		return i
+	}
+
+	for {
+		tt := z.Next()
+		if tt != html.TextToken {
+			inDD = false
+		}
+
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return bookmarks, nil
+			}
+			return nil, z.Err()
+		case html.TextToken:
+			if inDD && last != nil {
+				last.Description = strings.TrimSpace(last.Description + string(z.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if string(name) == "dl" && len(folders) > 0 {
+				folders = folders[:len(folders)-1]
+				indexes = indexes[:len(indexes)-1]
+			}
+		case html.StartTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "dl":
+				folders = append(folders, folder)
+				indexes = append(indexes, 0)
+				folder = ""
+			case "h3":
+				nextIndex()
+				folder = readHTMLText(z, "h3")
+				last = nil
+			case "a":
+				attrs := readHTMLAttrs(z, hasAttr)
+				index := nextIndex()
+				title := readHTMLText(z, "a")
+				uri := attrs["href"]
+				u, err := parseURL(uri)
+				if err != nil {
+					last = nil
+					continue
+				}
+
+				last = &Bookmark{
+					BookmarkerName: HTML,
+					Folder:         filepath.Join(append([]string{"/"}, folders...)...),
+					Title:          title,
+					URI:            uri,
+					Domain:         u.Host,
+					Added:          convertUnixTime(attrs["add_date"]),
+					Modified:       convertUnixTime(attrs["last_modified"]),
+					Tags:           splitTags(attrs["tags"]),
+					Keyword:        attrs["shortcuturl"],
+					Index:          index,
+				}
+				bookmarks = append(bookmarks, last)
+			case "dd":
+				inDD = true
+			}
+		}
+	}
+}
+
+// readHTMLText returns text until the end tag
+func readHTMLText(z *html.Tokenizer, tag string) string {
+	var text strings.Builder
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(text.String())
+		case html.TextToken:
+			text.Write(z.Text())
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == tag {
+				return strings.TrimSpace(text.String())
+			}
+		}
+	}
+}
+
+// readHTMLAttrs returns attributes of the current tag. keys are lower case
+func readHTMLAttrs(z *html.Tokenizer, hasAttr bool) map[string]string {
+	attrs := make(map[string]string)
+	for hasAttr {
+		var key, val []byte
+		key, val, hasAttr = z.TagAttr()
+		attrs[string(key)] = string(val)
+	}
+	return attrs
+}
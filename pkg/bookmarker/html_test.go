@@ -0,0 +1,96 @@
+package bookmarker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testHTMLBookmarkFile = filepath.Join(testdataPath, "test-bookmarks.html")
+var testHTMLBookmarks = Bookmarks{
+	&Bookmark{
+		BookmarkerName: HTML,
+		Folder:         "/Bookmarks Bar",
+		Title:          "Google",
+		Domain:         "www.google.com",
+		URI:            "https://www.google.com/",
+		Added:          time.Date(2019, 12, 9, 14, 8, 23, 0, time.UTC),
+		Tags:           []string{"search", "daily"},
+	},
+	&Bookmark{
+		BookmarkerName: HTML,
+		Folder:         "/Bookmarks Bar/1-hierarchy-a",
+		Title:          "GitHub",
+		Domain:         "github.com",
+		URI:            "https://github.com/",
+		Added:          time.Date(2019, 12, 4, 13, 39, 13, 0, time.UTC),
+		Modified:       time.Date(2019, 12, 4, 13, 39, 32, 0, time.UTC),
+		Keyword:        "gh",
+		Description:    "Where the world builds software",
+	},
+	&Bookmark{
+		BookmarkerName: HTML,
+		Folder:         "/Bookmarks Bar/1-hierarchy-a",
+		Title:          "Stack & Overflow",
+		Domain:         "stackoverflow.com",
+		URI:            "https://stackoverflow.com/",
+		Added:          time.Date(2019, 12, 9, 14, 5, 44, 0, time.UTC),
+		Index:          1,
+	},
+	&Bookmark{
+		BookmarkerName: HTML,
+		Folder:         "/",
+		Title:          "Amazon",
+		Domain:         "www.amazon.com",
+		URI:            "https://www.amazon.com/",
+		Added:          time.Date(2019, 11, 27, 13, 13, 6, 0, time.UTC),
+		Index:          1,
+	},
+	&Bookmark{
+		BookmarkerName: HTML,
+		Folder:         "/",
+		Title:          "Amazon Web Services",
+		Domain:         "aws.amazon.com",
+		URI:            "https://aws.amazon.com/?nc1=h_ls",
+		Index:          3,
+	},
+}
+
+func TestHTMLBookmarks(t *testing.T) {
+	tests := []struct {
+		name         string
+		bookmarkPath string
+		want         Bookmarks
+		expectErr    bool
+	}{
+		{
+			name:         "valid bookmark file",
+			bookmarkPath: testHTMLBookmarkFile,
+			want:         testHTMLBookmarks,
+		},
+		{
+			name:         "invalid bookmark file",
+			bookmarkPath: "test",
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewHTML(tt.bookmarkPath)
+			bookmarks, err := b.Bookmarks()
+
+			if tt.expectErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error got: %+v", err)
+			}
+
+			diff := DiffBookmark(bookmarks, tt.want)
+			if !tt.expectErr && diff != "" {
+				t.Errorf("+want -got\n%+v", diff)
+			}
+		})
+	}
+}
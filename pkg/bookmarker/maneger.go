@@ -1,38 +1,116 @@
 package bookmarker
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
 
 // Manager determine which bookmark read from
 type Manager struct {
-	bookmarkers      map[bookmarkerName]Bookmarker
+	bookmarkers      map[bookmarkerName][]*sourceBookmark
 	removeDuplicates bool
+	normalizer       *URLNormalizer
+	priority         []bookmarkerName
+	visits           bool
+	historyLimit     HistoryLimit
+	// faviconDir is a directory of the icon cache. favicons are not read if empty
+	faviconDir string
+	// errs are errors of sources which are not available. they are returned with bookmarks of the others
+	errs LoadErrors
 }
 
 // Option is the type to replace default parameters.
 type Option func(m *Manager) error
 
-// WithFirefox if called, search firefox bookmark
-func WithFirefox(profilePath, profileName string) Option {
+// WithFirefox if called, search firefox bookmark of the profiles.
+// If no profile name is passed, every profile in profilePath is searched.
+// Bookmarks are read from places.sqlite and the latest bookmark backup is used as a fallback
+func WithFirefox(profilePath string, profileNames ...string) Option {
 	return func(m *Manager) error {
-		path, err := GetFirefoxBookmarkFile(profilePath, profileName)
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, FirefoxProfiles, FirefoxProfileList)
 		if err != nil {
-			return err
+			m.addError(Firefox, "", err)
+			return nil
 		}
 
-		m.bookmarkers[Firefox] = NewFirefox(path)
+		for _, profileDirName := range profileDirNames {
+			b := new(firefoxFallbackBookmark)
+			placesPath, placesErr := GetFirefoxPlacesFile(profilePath, profileDirName)
+			if placesErr == nil {
+				b.places = NewFirefoxPlaces(placesPath)
+			}
+			backupPath, backupErr := GetFirefoxBookmarkFile(profilePath, profileDirName)
+			if backupErr == nil {
+				b.backup = NewFirefox(backupPath)
+			}
+			if placesErr != nil && backupErr != nil {
+				m.addError(Firefox, profileDirName, fmt.Errorf("%w (backup: %s)", placesErr, backupErr))
+				continue
+			}
+
+			m.add(Firefox, profileDirName, b)
+		}
 		return nil
 	}
 }
 
 // WithChrome if called, search chrome bookmark
-func WithChrome(profilePath, profileName string) Option {
+func WithChrome(profilePath string, profileNames ...string) Option {
+	return withChromium(Chrome, profilePath, profileNames)
+}
+
+// WithBrave if called, search brave bookmark
+func WithBrave(profilePath string, profileNames ...string) Option {
+	return withChromium(Brave, profilePath, profileNames)
+}
+
+// WithEdge if called, search microsoft edge bookmark
+func WithEdge(profilePath string, profileNames ...string) Option {
+	return withChromium(Edge, profilePath, profileNames)
+}
+
+// WithVivaldi if called, search vivaldi bookmark
+func WithVivaldi(profilePath string, profileNames ...string) Option {
+	return withChromium(Vivaldi, profilePath, profileNames)
+}
+
+// WithArc if called, search arc bookmark
+func WithArc(profilePath string, profileNames ...string) Option {
+	return withChromium(Arc, profilePath, profileNames)
+}
+
+// WithOpera if called, search opera bookmark.
+// Opera has no profile directory, so profileName is usually empty
+func WithOpera(profilePath string, profileNames ...string) Option {
+	return withChromium(Opera, profilePath, profileNames)
+}
+
+// WithChromium if called, search chromium bookmark
+func WithChromium(profilePath string, profileNames ...string) Option {
+	return withChromium(Chromium, profilePath, profileNames)
+}
+
+// withChromium searches bookmarks of the profiles.
+// If no profile name is passed, every profile in profilePath is searched.
+func withChromium(name bookmarkerName, profilePath string, profileNames []string) Option {
 	return func(m *Manager) error {
-		path, err := GetChromeBookmarkFile(profilePath, profileName)
+		profileDirNames, err := resolveProfileDirNames(profilePath, profileNames, ChromiumProfiles, ChromiumProfileList)
 		if err != nil {
-			return err
+			m.addError(name, "", err)
+			return nil
 		}
 
-		m.bookmarkers[Chrome] = NewChrome(path)
+		for _, profileDirName := range profileDirNames {
+			path, err := GetChromiumBookmarkFile(name, profilePath, profileDirName)
+			if err != nil {
+				m.addError(name, profileDirName, err)
+				continue
+			}
+
+			m.add(name, profileDirName, newChromium(name, path))
+		}
 		return nil
 	}
 }
@@ -42,15 +120,26 @@ func WithSafari() Option {
 	return func(m *Manager) error {
 		path, err := GetSafariBookmarkFile()
 		if err != nil {
-			return err
+			m.addError(Safari, "", err)
+			return nil
 		}
 
-		m.bookmarkers[Safari] = NewSafari(path)
+		m.add(Safari, "", NewSafari(path))
+		return nil
+	}
+}
+
+// WithHTML if called, search bookmarks of html files of NETSCAPE-Bookmark-file-1 format
+func WithHTML(paths ...string) Option {
+	return func(m *Manager) error {
+		for _, path := range paths {
+			m.add(HTML, "", NewHTML(path))
+		}
 		return nil
 	}
 }
 
-// WithRemoveDuplicates removes same bookmarks by urls
+// WithRemoveDuplicates merges same bookmarks by canonical forms of urls
 func WithRemoveDuplicates() Option {
 	return func(m *Manager) error {
 		m.removeDuplicates = true
@@ -58,10 +147,33 @@ func WithRemoveDuplicates() Option {
 	}
 }
 
+// WithBrowserPriority decides which bookmark wins when duplicate bookmarks are merged.
+// Browsers which are not passed follow them in name order
+func WithBrowserPriority(names ...string) Option {
+	return func(m *Manager) error {
+		m.priority = make([]bookmarkerName, 0, len(names))
+		for _, name := range names {
+			m.priority = append(m.priority, bookmarkerName(name))
+		}
+		return nil
+	}
+}
+
+// WithURLNormalizer replaces the default normalizer to find duplicate bookmarks.
+// If n is nil, urls are compared exactly
+func WithURLNormalizer(n *URLNormalizer) Option {
+	return func(m *Manager) error {
+		m.normalizer = n
+		return nil
+	}
+}
+
 // New is a managed bookmarker to get each bookmarks
 func New(opts ...Option) (Bookmarker, error) {
 	m := &Manager{
-		bookmarkers: make(map[bookmarkerName]Bookmarker),
+		bookmarkers:  make(map[bookmarkerName][]*sourceBookmark),
+		normalizer:   NewURLNormalizer(),
+		historyLimit: defaultHistoryLimit,
 	}
 
 	for _, opt := range opts {
@@ -77,25 +189,245 @@ func New(opts ...Option) (Bookmarker, error) {
 	return m, nil
 }
 
-// Bookmarks return Bookmarks struct by loading each bookmarker
-func (m *Manager) Bookmarks() (bookmarks Bookmarks, err error) {
-	for _, name := range getSupportedBookmarkerNames() {
-		bookmarker, ok := m.bookmarkers[name]
-		if !ok {
-			continue
+// add registers a bookmarker of the profile
+func (m *Manager) add(name bookmarkerName, profile string, b Bookmarker) {
+	m.bookmarkers[name] = append(m.bookmarkers[name], &sourceBookmark{
+		Bookmarker: b,
+		name:       name,
+		profile:    profile,
+	})
+}
+
+// addError records an error of the source which is not available.
+// It is returned as LoadError by Bookmarks not to fail the other sources
+func (m *Manager) addError(name bookmarkerName, profile string, err error) {
+	m.errs = append(m.errs, &LoadError{
+		BookmarkerName: name,
+		Profile:        profile,
+		Err:            err,
+	})
+}
+
+// Bookmarks return Bookmarks struct by loading each bookmarker concurrently.
+// Bookmarks which are loaded successfully are returned even if some bookmarkers fail.
+// In that case, the error is LoadErrors which has the failed sources
+func (m *Manager) Bookmarks() (Bookmarks, error) {
+	type result struct {
+		bookmarks Bookmarks
+		favicons  map[string][]byte
+		err       *LoadError
+	}
+
+	names := make([]bookmarkerName, 0, len(m.bookmarkers))
+	for name := range m.bookmarkers {
+		names = append(names, name)
+	}
+	// sort by name asc for making idempotency result
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	// results keep the order of names and bookmarkers
+	results := make([][]result, 0, len(names))
+	visits, favicons := m.visits, m.faviconDir != ""
+	wg := new(sync.WaitGroup)
+	for _, name := range names {
+		bookmarkers := m.bookmarkers[name]
+		rs := make([]result, len(bookmarkers))
+		for i, bookmarker := range bookmarkers {
+			if hb, ok := bookmarker.Bookmarker.(historyBookmarker); ok {
+				hb.setLimit(m.historyLimit)
+			}
+			wg.Add(1)
+			go func(name bookmarkerName, bookmarker *sourceBookmark, r *result) {
+				defer wg.Done()
+				b, err := bookmarker.Bookmarks()
+				if err != nil {
+					r.err = &LoadError{
+						BookmarkerName: name,
+						Profile:        bookmarker.profile,
+						Err:            err,
+					}
+					return
+				}
+				if visits {
+					b = attachVisits(b, bookmarker.Bookmarker)
+				}
+				if favicons {
+					r.favicons = readFavicons(bookmarker.Bookmarker)
+				}
+				r.bookmarks = b
+			}(name, bookmarker, &rs[i])
 		}
+		results = append(results, rs)
+	}
+	wg.Wait()
 
-		b, err := bookmarker.Bookmarks()
-		if err != nil {
-			// Note： not continue but return err if error occurs
-			return bookmarks, fmt.Errorf("failed to load bookmarks in %s: %w", name, err)
+	var bookmarks Bookmarks
+	errs := append(LoadErrors(nil), m.errs...)
+	var icons []map[string][]byte
+	for _, rs := range results {
+		for _, r := range rs {
+			if r.err != nil {
+				errs = append(errs, r.err)
+				continue
+			}
+			bookmarks = append(bookmarks, r.bookmarks...)
+			icons = append(icons, r.favicons)
 		}
-		bookmarks = append(bookmarks, b...)
+	}
+	if favicons {
+		bookmarks = attachFavicons(bookmarks, m.faviconDir, icons...)
 	}
 
+	// Note: history entries of bookmarked urls are redundant
+	bookmarks = bookmarks.withoutBookmarked(m.normalizer)
 	if m.removeDuplicates {
-		bookmarks = bookmarks.uniqByURI()
+		bookmarks = bookmarks.mergeByURI(m.normalizer, m.priority)
 	}
 
+	if len(errs) > 0 {
+		return bookmarks, errs
+	}
 	return bookmarks, nil
 }
+
+// Files returns files of all bookmarkers which implement FileSource.
+// History and favicon databases are also included if visits and favicons are attached to bookmarks
+func (m *Manager) Files() (files []string) {
+	for _, bookmarkers := range m.bookmarkers {
+		for _, b := range bookmarkers {
+			files = appendFiles(files, b.Bookmarker)
+			if vs, ok := b.Bookmarker.(visitFileSource); ok && m.visits {
+				files = append(files, vs.visitFiles()...)
+			}
+			if fs, ok := b.Bookmarker.(faviconFileSource); ok && m.faviconDir != "" {
+				files = append(files, fs.faviconFiles()...)
+			}
+		}
+	}
+	return files
+}
+
+// volatileFiles returns files in Files which browsers write on every visit like places.sqlite and history databases
+func (m *Manager) volatileFiles() (files []string) {
+	for _, bookmarkers := range m.bookmarkers {
+		for _, b := range bookmarkers {
+			if vs, ok := b.Bookmarker.(volatileFileSource); ok {
+				files = append(files, vs.volatileFiles()...)
+			}
+			if vs, ok := b.Bookmarker.(visitFileSource); ok && m.visits {
+				files = append(files, vs.visitFiles()...)
+			}
+			if fs, ok := b.Bookmarker.(faviconFileSource); ok && m.faviconDir != "" {
+				files = append(files, fs.faviconFiles()...)
+			}
+		}
+	}
+	return files
+}
+
+// appendFiles appends files of the bookmarker if it implements FileSource
+func appendFiles(files []string, b Bookmarker) []string {
+	if fb, ok := b.(FileSource); ok {
+		files = append(files, fb.Files()...)
+	}
+	return files
+}
+
+// LoadedFileSource is implemented by bookmarkers which may load bookmarks from a fallback file
+type LoadedFileSource interface {
+	// LoadedFiles returns files which bookmarks are loaded from by the last call of Bookmarks
+	LoadedFiles() []*LoadedFile
+}
+
+// LoadedFile is a file which bookmarks are loaded from
+type LoadedFile struct {
+	BookmarkerName bookmarkerName
+	// Profile is a directory name of the browser profile. empty if the browser has no profile
+	Profile string
+	Path    string
+	// Fallback is the reason why the file is loaded instead of the primary file. nil if it is the primary file
+	Fallback error
+	// Warning is a problem of the loaded file like a checksum mismatch. nil if the file is valid
+	Warning error
+}
+
+// LoadedFiles returns files of bookmarkers which implement LoadedFileSource in name order.
+// It is available after calling Bookmarks
+func (m *Manager) LoadedFiles() []*LoadedFile {
+	names := make([]bookmarkerName, 0, len(m.bookmarkers))
+	for name := range m.bookmarkers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	var files []*LoadedFile
+	for _, name := range names {
+		for _, b := range m.bookmarkers[name] {
+			ls, ok := b.Bookmarker.(LoadedFileSource)
+			if !ok {
+				continue
+			}
+			for _, f := range ls.LoadedFiles() {
+				f.BookmarkerName, f.Profile = name, b.profile
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// LoadError is an error of a bookmarker which failed to load bookmarks
+type LoadError struct {
+	BookmarkerName bookmarkerName
+	// Profile is a directory name of the browser profile. empty if the browser has no profile
+	Profile string
+	Err     error
+}
+
+func (e *LoadError) Error() string {
+	if e.Profile == "" {
+		return fmt.Sprintf("failed to load bookmarks in %s: %s", e.BookmarkerName, e.Err)
+	}
+	return fmt.Sprintf("failed to load bookmarks in %s (%s): %s", e.BookmarkerName, e.Profile, e.Err)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// LoadErrors is a list of LoadError returned by Manager.Bookmarks
+type LoadErrors []*LoadError
+
+func (errs LoadErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// sourceBookmark records a source and a profile which bookmarks come from
+type sourceBookmark struct {
+	Bookmarker
+	name    bookmarkerName
+	profile string
+}
+
+// Bookmarks return bookmarks of the source.
+// BookmarkerName is filled in if the bookmarker leaves it empty
+func (b *sourceBookmark) Bookmarks() (Bookmarks, error) {
+	bookmarks, err := b.Bookmarker.Bookmarks()
+	for _, bookmark := range bookmarks {
+		if bookmark.BookmarkerName == "" {
+			bookmark.BookmarkerName = b.name
+		}
+		if b.profile != "" {
+			bookmark.Profile = b.profile
+		}
+	}
+	return bookmarks, err
+}
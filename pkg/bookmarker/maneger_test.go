@@ -1,11 +1,33 @@
 package bookmarker
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 var testProfile = "default"
 
+// testFirefoxProfileDir is a profile directory name created by setup-test-dir.sh
+var testFirefoxProfileDir = "xxxxx.default"
+
+// testSecondProfile is a second chrome profile created by setup-test-dir.sh
+var testSecondProfile = "Profile 1"
+
+// withProfile returns copies of bookmarks which come from the profile
+func withProfile(profile string, bookmarks Bookmarks) Bookmarks {
+	ret := make(Bookmarks, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		c := *b
+		c.Profile = profile
+		ret = append(ret, &c)
+	}
+	return ret
+}
+
 func TestEngineBookmarks(t *testing.T) {
 	tests := []struct {
 		description string
@@ -18,14 +40,32 @@ func TestEngineBookmarks(t *testing.T) {
 			options: []Option{
 				WithFirefox(defaultFirefoxProfilePath, testProfile),
 			},
-			want: testFirefoxBookmarks,
+			want: withProfile(testFirefoxProfileDir, testFirefoxBookmarks),
 		},
 		{
 			description: "enable chrome bookmark",
 			options: []Option{
 				WithChrome(defaultChromeProfilePath, testProfile),
 			},
-			want: testChromeBookmarks,
+			want: withProfile(testProfile, testChromeBookmarks),
+		},
+		{
+			description: "enable chrome bookmark of multiple profiles",
+			options: []Option{
+				WithChrome(defaultChromeProfilePath, testProfile, testSecondProfile),
+			},
+			want: append(
+				withProfile(testProfile, testChromeBookmarks),
+				withProfile(testSecondProfile, testChromeBookmarks)...),
+		},
+		{
+			description: "enable chrome bookmark of all profiles",
+			options: []Option{
+				WithChrome(defaultChromeProfilePath),
+			},
+			want: append(
+				withProfile(testProfile, testChromeBookmarks),
+				withProfile(testSecondProfile, testChromeBookmarks)...),
 		},
 		{
 			description: "enable safari bookmark",
@@ -34,6 +74,20 @@ func TestEngineBookmarks(t *testing.T) {
 			},
 			want: testSafariBookmarks,
 		},
+		{
+			description: "enable brave bookmark",
+			options: []Option{
+				WithBrave(defaultBraveProfilePath, testProfile),
+			},
+			want: withProfile(testProfile, testChromiumBookmarks(Brave)),
+		},
+		{
+			description: "enable opera bookmark which has no profile directory",
+			options: []Option{
+				WithOpera(defaultOperaProfilePath, ""),
+			},
+			want: testChromiumBookmarks(Opera),
+		},
 	}
 
 	for _, tt := range tests {
@@ -81,10 +135,106 @@ func TestOptionFirefoxChrome(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
-			// panic if error occurs
-			if _, err := New(tt.options...); err != nil {
-				t.Error(err)
+			m, err := New(tt.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errs := m.(*Manager).errs; len(errs) > 0 {
+				t.Error(errs)
 			}
 		})
 	}
 }
+
+func TestManagerUnavailableSources(t *testing.T) {
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chromeProfilePath := t.TempDir()
+	for _, dir := range []string{"Default", "Profile 1"} {
+		if err := os.Mkdir(filepath.Join(chromeProfilePath, dir), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, filepath.Join(chromeProfilePath, "Default", "Bookmarks"), string(data))
+
+	m, err := New(
+		// Profile 1 has no bookmark file
+		WithChrome(chromeProfilePath, "Default", "Profile 1"),
+		// the profile does not exist
+		WithFirefox(t.TempDir(), "default"),
+		WithSource(string(Brave), &ProfileConfig{Enable: true, ProfileName: "default", ProfilePath: t.TempDir()}),
+	)
+	if err != nil {
+		t.Fatalf("unavailable sources should not fail the others: %v", err)
+	}
+
+	bookmarks, err := m.Bookmarks()
+	var loadErrs LoadErrors
+	if !errors.As(err, &loadErrs) {
+		t.Fatalf("want LoadErrors, got %v", err)
+	}
+	want := []struct {
+		name    bookmarkerName
+		profile string
+	}{
+		{name: Chrome, profile: "Profile 1"},
+		{name: Firefox},
+		{name: Brave},
+	}
+	if len(loadErrs) != len(want) {
+		t.Fatalf("want %d errors, got %v", len(want), loadErrs)
+	}
+	for i, w := range want {
+		if loadErrs[i].BookmarkerName != w.name || loadErrs[i].Profile != w.profile {
+			t.Errorf("want %s (%s), got %s (%s)", w.name, w.profile, loadErrs[i].BookmarkerName, loadErrs[i].Profile)
+		}
+	}
+
+	wantBookmarks := testChromiumBookmarks(Chrome)
+	for _, b := range wantBookmarks {
+		b.Profile = "Default"
+	}
+	if diff := DiffBookmark(bookmarks, wantBookmarks); diff != "" {
+		t.Errorf("+want -got\n%+v", diff)
+	}
+}
+
+func TestManagerBookmarksPartialFailure(t *testing.T) {
+	m := &Manager{
+		bookmarkers: make(map[bookmarkerName][]*sourceBookmark),
+	}
+	m.add(Safari, "", NewSafari("test"))
+	m.add(Chrome, testProfile, NewChrome(testChromeBookmarkJSONFile))
+	m.add(Chrome, testSecondProfile, NewChrome("test"))
+	m.add(Firefox, "", NewFirefox(testFirefoxBookmarkJsonlz4File))
+
+	bookmarks, err := m.Bookmarks()
+	if err == nil {
+		t.Fatal("expect error happens, but got response")
+	}
+
+	// bookmarks keep the order of the browser names and the profiles
+	chrome, err1 := NewChrome(testChromeBookmarkJSONFile).Bookmarks()
+	firefox, err2 := NewFirefox(testFirefoxBookmarkJsonlz4File).Bookmarks()
+	if err1 != nil || err2 != nil {
+		t.Fatal(err1, err2)
+	}
+	want := append(withProfile(testProfile, chrome), firefox...)
+	if diff := cmp.Diff(want, bookmarks); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+
+	var errs LoadErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expect LoadErrors but got %T", err)
+	}
+	got := make([]string, 0, len(errs))
+	for _, e := range errs {
+		got = append(got, string(e.BookmarkerName)+":"+e.Profile)
+	}
+	if diff := cmp.Diff([]string{"chrome:" + testSecondProfile, "safari:"}, got); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+}
@@ -0,0 +1,185 @@
+package bookmarker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// Profile is a browser profile
+type Profile struct {
+	// Name is a display name of the profile
+	Name string
+	// DirName is a directory name of the profile in the profile path
+	DirName string
+	// Default is true if the browser uses the profile by default
+	Default bool
+}
+
+// profileLister returns profiles which the browser knows in the profile path
+type profileLister func(profilePath string) ([]*Profile, error)
+
+// FirefoxProfileList returns profiles of profiles.ini and installs.ini in the parent directory of profilePath
+// e.g.) FirefoxProfileList(
+//
+//	os.ExpandEnv("${HOME}/Library/Application Support/Firefox/Profiles"))
+func FirefoxProfileList(profilePath string) ([]*Profile, error) {
+	baseDir := filepath.Dir(profilePath)
+	cfg, err := ini.Load(filepath.Join(baseDir, "profiles.ini"))
+	if err != nil {
+		return nil, fmt.Errorf("firefox error: %w", err)
+	}
+
+	// relPath converts a path of ini files into a directory name in profilePath
+	relPath := func(path string, isRelative bool) string {
+		if isRelative {
+			path = filepath.Join(baseDir, filepath.FromSlash(path))
+		}
+		rel, err := filepath.Rel(profilePath, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return ""
+		}
+		return rel
+	}
+
+	// a default profile of an installation has priority over `Default=1` of a profile
+	installDefaults := make(map[string]bool)
+	if installs, err := ini.Load(filepath.Join(baseDir, "installs.ini")); err == nil {
+		for _, sec := range installs.Sections() {
+			if sec.HasKey("Default") {
+				installDefaults[relPath(sec.Key("Default").String(), true)] = true
+			}
+		}
+	}
+	for _, sec := range cfg.Sections() {
+		if strings.HasPrefix(sec.Name(), "Install") && sec.HasKey("Default") {
+			installDefaults[relPath(sec.Key("Default").String(), true)] = true
+		}
+	}
+
+	profiles := []*Profile{}
+	for _, sec := range cfg.Sections() {
+		if !strings.HasPrefix(sec.Name(), "Profile") || !sec.HasKey("Path") {
+			continue
+		}
+
+		dirName := relPath(sec.Key("Path").String(), sec.Key("IsRelative").MustBool(true))
+		if dirName == "" {
+			continue
+		}
+		profiles = append(profiles, &Profile{
+			Name:    sec.Key("Name").String(),
+			DirName: dirName,
+			Default: sec.Key("Default").MustBool(false),
+		})
+	}
+
+	if len(installDefaults) > 0 {
+		for _, p := range profiles {
+			p.Default = installDefaults[p.DirName]
+		}
+	}
+	return profiles, nil
+}
+
+// chromeLocalState is a part of `Local State` file which has profile information
+type chromeLocalState struct {
+	Profile struct {
+		InfoCache map[string]struct {
+			Name string `json:"name"`
+		} `json:"info_cache"`
+		LastUsed string `json:"last_used"`
+	} `json:"profile"`
+}
+
+// ChromiumProfileList returns profiles of `Local State` in profilePath
+// e.g.) ChromiumProfileList(
+//
+//	os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome"))
+func ChromiumProfileList(profilePath string) ([]*Profile, error) {
+	f, err := os.Open(filepath.Join(profilePath, "Local State"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	state := new(chromeLocalState)
+	if err := json.NewDecoder(f).Decode(state); err != nil {
+		return nil, fmt.Errorf("failed to decode Local State: %w", err)
+	}
+
+	lastUsed := state.Profile.LastUsed
+	if lastUsed == "" {
+		// chrome uses `Default` directory if no profile was used
+		lastUsed = "Default"
+	}
+
+	profiles := make([]*Profile, 0, len(state.Profile.InfoCache))
+	for dirName, info := range state.Profile.InfoCache {
+		profiles = append(profiles, &Profile{
+			Name:    info.Name,
+			DirName: dirName,
+			Default: dirName == lastUsed,
+		})
+	}
+	// sort by directory name for making idempotency result
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].DirName < profiles[j].DirName
+	})
+	return profiles, nil
+}
+
+// DefaultFirefoxProfile returns a profile which firefox uses by default
+func DefaultFirefoxProfile(profilePath string) (*Profile, error) {
+	return defaultProfile(profilePath, FirefoxProfileList)
+}
+
+// DefaultChromiumProfile returns a profile which chrome or chromium-based browsers use by default
+func DefaultChromiumProfile(profilePath string) (*Profile, error) {
+	return defaultProfile(profilePath, ChromiumProfileList)
+}
+
+func defaultProfile(profilePath string, list profileLister) (*Profile, error) {
+	profiles, err := list(profilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range profiles {
+		if p.Default {
+			return p, nil
+		}
+	}
+	return nil, errors.New("not found a default profile in " + profilePath)
+}
+
+// searchProfileDir returns a directory name of the profile.
+// profileName is compared with directory names, display names of the profiles and suffixes of directory names in order
+func searchProfileDir(dir, profileName string, list profileLister) (string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		if name := file.Name(); file.IsDir() && strings.EqualFold(name, profileName) {
+			return name, nil
+		}
+	}
+
+	if profiles, err := list(dir); err == nil {
+		for _, p := range profiles {
+			if strings.EqualFold(p.Name, profileName) {
+				return p.DirName, nil
+			}
+		}
+	}
+
+	return searchSuffixDir(dir, profileName)
+}
@@ -0,0 +1,238 @@
+package bookmarker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testFirefoxProfilesIni = `[Install4F96D1932A9F858E]
+Default=Profiles/abcdefgh.default-release
+Locked=1
+
+[Profile1]
+Name=default
+IsRelative=1
+Path=Profiles/ijklmnop.default
+Default=1
+
+[Profile0]
+Name=default-release
+IsRelative=1
+Path=Profiles/abcdefgh.default-release
+
+[Profile2]
+Name=work
+IsRelative=1
+Path=Profiles/qrstuvwx.work
+
+[General]
+StartWithLastProfile=1
+Version=2
+`
+
+const testFirefoxInstallsIni = `[4F96D1932A9F858E]
+Default=Profiles/abcdefgh.default-release
+Locked=1
+`
+
+const testChromeLocalState = `{
+  "profile": {
+    "info_cache": {
+      "Default": {"name": "Person 1"},
+      "Profile 1": {"name": "Work"},
+      "Profile 2": {"name": "Private"}
+    },
+    "last_used": "Profile 1"
+  }
+}`
+
+// createTestFirefoxProfiles creates a firefox directory which has profiles.ini and returns the profile path
+func createTestFirefoxProfiles(t *testing.T, installs bool) string {
+	t.Helper()
+	base := t.TempDir()
+	profilePath := filepath.Join(base, "Profiles")
+	for _, dir := range []string{"abcdefgh.default-release", "ijklmnop.default", "qrstuvwx.work"} {
+		if err := os.MkdirAll(filepath.Join(profilePath, dir), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, filepath.Join(base, "profiles.ini"), testFirefoxProfilesIni)
+	if installs {
+		writeTestFile(t, filepath.Join(base, "installs.ini"), testFirefoxInstallsIni)
+	}
+	return profilePath
+}
+
+// createTestChromeProfiles creates a chrome directory which has Local State and returns the profile path
+func createTestChromeProfiles(t *testing.T) string {
+	t.Helper()
+	profilePath := t.TempDir()
+	for _, dir := range []string{"Default", "Profile 1", "Profile 2"} {
+		if err := os.MkdirAll(filepath.Join(profilePath, dir), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, filepath.Join(profilePath, "Local State"), testChromeLocalState)
+	return profilePath
+}
+
+func writeTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFirefoxProfileList(t *testing.T) {
+	profilePath := createTestFirefoxProfiles(t, true)
+	want := []*Profile{
+		{Name: "default", DirName: "ijklmnop.default"},
+		{Name: "default-release", DirName: "abcdefgh.default-release", Default: true},
+		{Name: "work", DirName: "qrstuvwx.work"},
+	}
+
+	got, err := FirefoxProfileList(profilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+
+	if _, err := FirefoxProfileList(t.TempDir()); err == nil {
+		t.Errorf("expect error happens if profiles.ini does not exist")
+	}
+}
+
+func TestChromiumProfileList(t *testing.T) {
+	profilePath := createTestChromeProfiles(t)
+	want := []*Profile{
+		{Name: "Person 1", DirName: "Default"},
+		{Name: "Work", DirName: "Profile 1", Default: true},
+		{Name: "Private", DirName: "Profile 2"},
+	}
+
+	got, err := ChromiumProfileList(profilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+
+	if _, err := ChromiumProfileList(t.TempDir()); err == nil {
+		t.Errorf("expect error happens if Local State does not exist")
+	}
+}
+
+func TestDefaultProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		profilePath string
+		lister      profileLister
+		want        string
+	}{
+		{
+			name:        "firefox default profile of installs.ini",
+			profilePath: createTestFirefoxProfiles(t, true),
+			lister:      FirefoxProfileList,
+			want:        "abcdefgh.default-release",
+		},
+		{
+			name:        "firefox default profile of [Install] section in profiles.ini",
+			profilePath: createTestFirefoxProfiles(t, false),
+			lister:      FirefoxProfileList,
+			want:        "abcdefgh.default-release",
+		},
+		{
+			name:        "chrome last used profile",
+			profilePath: createTestChromeProfiles(t),
+			lister:      ChromiumProfileList,
+			want:        "Profile 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := defaultProfile(tt.profilePath, tt.lister)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.DirName != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got.DirName)
+			}
+		})
+	}
+}
+
+func Test_searchProfileDir(t *testing.T) {
+	firefoxProfilePath := createTestFirefoxProfiles(t, true)
+	chromeProfilePath := createTestChromeProfiles(t)
+	tests := []struct {
+		name        string
+		profilePath string
+		profileName string
+		lister      profileLister
+		want        string
+		expectErr   bool
+	}{
+		{
+			name:        "directory name",
+			profilePath: chromeProfilePath,
+			profileName: "profile 2",
+			lister:      ChromiumProfileList,
+			want:        "Profile 2",
+		},
+		{
+			name:        "chrome display name",
+			profilePath: chromeProfilePath,
+			profileName: "Work",
+			lister:      ChromiumProfileList,
+			want:        "Profile 1",
+		},
+		{
+			name:        "firefox display name",
+			profilePath: firefoxProfilePath,
+			profileName: "default-release",
+			lister:      FirefoxProfileList,
+			want:        "abcdefgh.default-release",
+		},
+		{
+			name:        "firefox display name has priority over suffix",
+			profilePath: firefoxProfilePath,
+			profileName: "default",
+			lister:      FirefoxProfileList,
+			want:        "ijklmnop.default",
+		},
+		{
+			name:        "suffix of directory name",
+			profilePath: firefoxProfilePath,
+			profileName: ".work",
+			lister:      FirefoxProfileList,
+			want:        "qrstuvwx.work",
+		},
+		{
+			name:        "not found",
+			profilePath: chromeProfilePath,
+			profileName: "unknown",
+			lister:      ChromiumProfileList,
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchProfileDir(tt.profilePath, tt.profileName, tt.lister)
+			if tt.expectErr && err == nil {
+				t.Errorf("expect error happens, but got response")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error got: %+v", err)
+			}
+			if got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
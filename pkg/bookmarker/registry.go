@@ -0,0 +1,109 @@
+package bookmarker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SourceConfig is a configuration of a source
+type SourceConfig interface {
+	// Enabled returns true if bookmarks of the source should be loaded
+	Enabled() bool
+}
+
+// Source is a bookmark source which can be registered in the registry.
+// Third-party bookmarkers are available by registering them with Register
+type Source struct {
+	// Name is a unique name of the source. It is also used as a key of the configuration file
+	Name string
+	// DisplayName is a name of the source for displaying
+	DisplayName string
+	// Icon is an image file name of the source
+	Icon string
+	// Badge is a short label of the source to show which sources have a bookmark
+	Badge string
+	// App is an application name to open urls of the source. empty if the source has no application
+	App string
+	// Decode returns a configuration of the source.
+	// decode overwrites default values of the configuration with values in the configuration file
+	Decode func(decode func(v interface{}) error) (SourceConfig, error)
+	// Detect returns a configuration if the source is available on the computer
+	Detect func() (SourceConfig, error)
+	// New returns an option to search bookmarks of the source with the configuration
+	New func(cfg SourceConfig) Option
+	// Add adds a bookmark of the url into the folder of the profile with the configuration.
+	// nil if the source does not support adding bookmarks
+	Add func(cfg SourceConfig, profile, folder, title, uri string) (*Bookmark, error)
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]*Source)
+)
+
+// Register makes a source available by the name.
+// If Register is called twice with the same name or the source lacks functions, it panics
+func Register(s *Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	if s == nil || s.Name == "" {
+		panic("bookmarker: Register source is nil or has no name")
+	}
+	if s.Decode == nil || s.Detect == nil || s.New == nil {
+		panic("bookmarker: Register source lacks functions " + s.Name)
+	}
+	if _, dup := sources[s.Name]; dup {
+		panic("bookmarker: Register called twice for source " + s.Name)
+	}
+	sources[s.Name] = s
+}
+
+// Sources returns registered sources sorted by name
+func Sources() []*Source {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+
+	ret := make([]*Source, 0, len(sources))
+	for _, s := range sources {
+		ret = append(ret, s)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// LookupSource returns the registered source of the name
+func LookupSource(name string) (*Source, bool) {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+
+	s, ok := sources[name]
+	return s, ok
+}
+
+// WithSource if called, search bookmarks of the registered source with the configuration
+func WithSource(name string, cfg SourceConfig) Option {
+	return func(m *Manager) error {
+		s, ok := LookupSource(name)
+		if !ok {
+			return fmt.Errorf("unknown source: %s", name)
+		}
+		// Note: a source which is not available does not fail the others
+		if err := s.New(cfg)(m); err != nil {
+			m.addError(bookmarkerName(name), "", err)
+		}
+		return nil
+	}
+}
+
+// WithBookmarker if called, search bookmarks of b as the source name.
+// Bookmarks which have no BookmarkerName are named after the source
+func WithBookmarker(name string, b Bookmarker) Option {
+	return func(m *Manager) error {
+		m.add(bookmarkerName(name), "", b)
+		return nil
+	}
+}
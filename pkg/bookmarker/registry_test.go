@@ -0,0 +1,191 @@
+package bookmarker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testSourceConfig struct {
+	Enable bool   `mapstructure:"enable"`
+	Path   string `mapstructure:"path"`
+}
+
+func (c *testSourceConfig) Enabled() bool {
+	return c.Enable
+}
+
+// registerTestSource registers a third-party source which reads chrome bookmarks without BookmarkerName
+func registerTestSource(t *testing.T, name string) {
+	t.Helper()
+	Register(&Source{
+		Name:        name,
+		DisplayName: "Test Source",
+		Decode: func(decode func(v interface{}) error) (SourceConfig, error) {
+			c := &testSourceConfig{Path: testChromeBookmarkJSONFile}
+			return c, decode(c)
+		},
+		Detect: func() (SourceConfig, error) {
+			return &testSourceConfig{Enable: true, Path: testChromeBookmarkJSONFile}, nil
+		},
+		New: func(cfg SourceConfig) Option {
+			return WithBookmarker(name, &noNameBookmark{NewChrome(cfg.(*testSourceConfig).Path)})
+		},
+	})
+	t.Cleanup(func() {
+		sourcesMu.Lock()
+		defer sourcesMu.Unlock()
+		delete(sources, name)
+	})
+}
+
+// noNameBookmark clears BookmarkerName like third-party bookmarkers which can not name it
+type noNameBookmark struct {
+	Bookmarker
+}
+
+func (b *noNameBookmark) Bookmarks() (Bookmarks, error) {
+	bookmarks, err := b.Bookmarker.Bookmarks()
+	for _, bookmark := range bookmarks {
+		bookmark.BookmarkerName = ""
+	}
+	return bookmarks, err
+}
+
+func TestRegister(t *testing.T) {
+	name := "test-source"
+	registerTestSource(t, name)
+
+	s, ok := LookupSource(name)
+	if !ok {
+		t.Fatalf("%s is not registered", name)
+	}
+	cfg, err := s.Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := New(WithSource(name, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bookmarks, err := m.Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range bookmarks {
+		if b.BookmarkerName != bookmarkerName(name) {
+			t.Errorf("want %s, got %s", name, b.BookmarkerName)
+		}
+	}
+	if len(bookmarks) != len(testChromeBookmarks) {
+		t.Errorf("want %d bookmarks, got %d", len(testChromeBookmarks), len(bookmarks))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expect panic if a source is registered twice")
+		}
+	}()
+	registerTestSource(t, name)
+}
+
+func TestSources(t *testing.T) {
+	want := []string{"arc", "brave", "chrome", "chrome_reading_list", "chrome_search_engines", "chromium", "edge", "firefox", "firefox_tabs", "html", "opera", "safari", "vivaldi"}
+	got := []string{}
+	for _, s := range Sources() {
+		got = append(got, s.Name)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+
+	// bookmarks can be added into bookmark files of chrome and chromium-based browsers
+	wantWritable := []string{"arc", "brave", "chrome", "chromium", "edge", "opera", "vivaldi"}
+	writable := []string{}
+	for _, s := range Sources() {
+		if s.Add != nil {
+			writable = append(writable, s.Name)
+		}
+	}
+	if diff := cmp.Diff(wantWritable, writable); diff != "" {
+		t.Errorf("-want +got\n%+v", diff)
+	}
+
+	if _, err := New(WithSource("unknown", nil)); err == nil {
+		t.Errorf("expect error happens if the source is not registered")
+	}
+}
+
+func TestProfileSourceDecode(t *testing.T) {
+	firefoxProfilePath := createTestFirefoxProfiles(t, true)
+	tests := []struct {
+		name   string
+		source string
+		config ProfileConfig
+		want   string
+	}{
+		{
+			name:   "omitted profile name is the default profile of installs.ini",
+			source: "firefox",
+			config: ProfileConfig{ProfilePath: firefoxProfilePath},
+			want:   "abcdefgh.default-release",
+		},
+		{
+			name:   "configured profile name is kept",
+			source: "firefox",
+			config: ProfileConfig{ProfilePath: firefoxProfilePath, ProfileName: "default"},
+			want:   "default",
+		},
+		{
+			name:   "omitted profile name is the last used profile of Local State",
+			source: "chrome",
+			config: ProfileConfig{ProfilePath: createTestChromeProfiles(t)},
+			want:   "Profile 1",
+		},
+		{
+			name:   "default profile name if the browser has no information of profiles",
+			source: "chrome",
+			config: ProfileConfig{ProfilePath: t.TempDir()},
+			want:   "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := LookupSource(tt.source)
+			if !ok {
+				t.Fatalf("%s is not registered", tt.source)
+			}
+			// decode overwrites the configuration like values of the configuration file
+			cfg, err := s.Decode(func(v interface{}) error {
+				c := v.(*ProfileConfig)
+				c.ProfilePath = tt.config.ProfilePath
+				if tt.config.ProfileName != "" {
+					c.ProfileName = tt.config.ProfileName
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cfg.(*ProfileConfig).ProfileName; got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOptInSources(t *testing.T) {
+	// sources which are not bookmarks are not detected not to change results of users without the configuration
+	for _, name := range []string{"firefox_tabs", "chrome_reading_list", "chrome_search_engines", "html"} {
+		t.Run(name, func(t *testing.T) {
+			s, ok := LookupSource(name)
+			if !ok {
+				t.Fatalf("%s is not registered", name)
+			}
+			if _, err := s.Detect(); err == nil {
+				t.Errorf("expect error happens as %s is opt-in", name)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"howett.net/plist"
 )
@@ -15,8 +16,25 @@ type safariBookmarkEntry struct {
 	WebBookmarkUUID string                 `plist:"WebBookmarkUUID"`
 	URIDictionary   map[string]string      `plist:"URIDictionary"`
 	Children        []*safariBookmarkEntry `plist:"Children"`
+	// ReadingList is set if the entry is an item of the reading list
+	ReadingList *safariReadingListItem `plist:"ReadingList,omitempty"`
+	// index is a position in the parent folder
+	index int
 }
 
+type safariReadingListItem struct {
+	DateAdded time.Time `plist:"DateAdded"`
+	// DateLastViewed is zero value if the item is unread
+	DateLastViewed time.Time `plist:"DateLastViewed"`
+	PreviewText    string    `plist:"PreviewText"`
+}
+
+// safariReadingListTitle is a title of the folder which safari saves the reading list in
+const safariReadingListTitle = "com.apple.ReadingList"
+
+// ReadingListFolder is a folder of reading list items
+const ReadingListFolder = "/Reading List"
+
 type safariBookmarkRoot struct {
 	root safariBookmarkEntry
 }
@@ -56,6 +74,11 @@ func (b *safariBookmark) load() error {
 	return nil
 }
 
+// Files returns the bookmark file
+func (b *safariBookmark) Files() []string {
+	return []string{b.bookmarkPath}
+}
+
 // convertToBookmarks parse a entry and children of the entry
 func (entry *safariBookmarkEntry) convertToBookmarks(folder string) (bookmarks Bookmarks) {
 	switch entry.WebBookmarkType {
@@ -63,10 +86,13 @@ func (entry *safariBookmarkEntry) convertToBookmarks(folder string) (bookmarks B
 		if entry.Children == nil {
 			return
 		}
-		if entry.Title != "" {
+		if entry.Title == safariReadingListTitle {
+			folder = ReadingListFolder
+		} else if entry.Title != "" {
 			folder = filepath.Join(folder, entry.Title)
 		}
-		for _, e := range entry.Children {
+		for i, e := range entry.Children {
+			e.index = i
 			bookmarks = append(bookmarks, e.convertToBookmarks(folder)...)
 		}
 	case "WebBookmarkTypeLeaf":
@@ -86,6 +112,14 @@ func (entry *safariBookmarkEntry) convertToBookmarks(folder string) (bookmarks B
 			Title:          title,
 			URI:            entry.URLString,
 			Domain:         u.Host,
+			ID:             entry.WebBookmarkUUID,
+			Index:          entry.index,
+		}
+		if r := entry.ReadingList; r != nil {
+			b.ReadingList = true
+			b.Unread = r.DateLastViewed.IsZero()
+			b.Added = r.DateAdded.UTC()
+			b.Description = r.PreviewText
 		}
 		bookmarks = append(bookmarks, b)
 	}
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"howett.net/plist"
 )
 
@@ -19,6 +21,7 @@ var testSafariBookmarks = Bookmarks{
 		Title:          "Stack Overflow",
 		Domain:         "stackoverflow.com",
 		URI:            "https://stackoverflow.com/",
+		ID:             "3858345F-C3F9-4C0A-B458-F25EE39D7913",
 	},
 	&Bookmark{
 		BookmarkerName: Safari,
@@ -26,6 +29,8 @@ var testSafariBookmarks = Bookmarks{
 		Title:          "Amazon Web Services",
 		Domain:         "aws.amazon.com",
 		URI:            "https://aws.amazon.com/?nc1=h_ls",
+		ID:             "3773D85C-8A04-40BA-89BE-6A95AE50C38E",
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Safari,
@@ -33,6 +38,7 @@ var testSafariBookmarks = Bookmarks{
 		Title:          "Yahoo",
 		Domain:         "www.yahoo.com",
 		URI:            "https://www.yahoo.com/",
+		ID:             "05D3D439-3AE6-4821-813A-B53285ED4BD3",
 	},
 	&Bookmark{
 		BookmarkerName: Safari,
@@ -40,6 +46,7 @@ var testSafariBookmarks = Bookmarks{
 		Title:          "Facebook",
 		Domain:         "www.facebook.com",
 		URI:            "https://www.facebook.com/",
+		ID:             "63F45D02-32EF-4292-BAC1-375A41FBE759",
 	},
 	&Bookmark{
 		BookmarkerName: Safari,
@@ -47,6 +54,8 @@ var testSafariBookmarks = Bookmarks{
 		Title:          "Twitter",
 		Domain:         "twitter.com",
 		URI:            "https://twitter.com/login",
+		ID:             "C08948A6-2E7D-42A4-8948-514ED0BAA55F",
+		Index:          1,
 	},
 	&Bookmark{
 		BookmarkerName: Safari,
@@ -54,6 +63,7 @@ var testSafariBookmarks = Bookmarks{
 		Title:          "Amazon.com",
 		Domain:         "www.amazon.com",
 		URI:            "https://www.amazon.com/",
+		ID:             "7332DD7E-04DE-4981-823A-2FEB4B193515",
 	},
 }
 
@@ -97,6 +107,77 @@ func TestSafariBookmarks(t *testing.T) {
 	}
 }
 
+func TestSafariReadingList(t *testing.T) {
+	added := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	root := &safariBookmarkEntry{
+		WebBookmarkType: "WebBookmarkTypeList",
+		Children: []*safariBookmarkEntry{
+			{
+				Title:           safariReadingListTitle,
+				WebBookmarkType: "WebBookmarkTypeList",
+				Children: []*safariBookmarkEntry{
+					{
+						WebBookmarkType: "WebBookmarkTypeLeaf",
+						URLString:       "https://go.dev/blog/",
+						WebBookmarkUUID: "1",
+						URIDictionary:   map[string]string{"title": "The Go Blog"},
+						ReadingList:     &safariReadingListItem{DateAdded: added, PreviewText: "news"},
+					},
+					{
+						WebBookmarkType: "WebBookmarkTypeLeaf",
+						URLString:       "https://go.dev/doc/",
+						WebBookmarkUUID: "2",
+						URIDictionary:   map[string]string{"title": "Documentation"},
+						ReadingList:     &safariReadingListItem{DateAdded: added, DateLastViewed: added.Add(time.Hour)},
+					},
+				},
+			},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "Bookmarks.plist")
+	w, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generatePlist(&safariBookmarkRoot{root: *root}, w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	got, err := NewSafari(path).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Bookmarks{
+		{
+			BookmarkerName: Safari,
+			Folder:         ReadingListFolder,
+			Title:          "The Go Blog",
+			Domain:         "go.dev",
+			URI:            "https://go.dev/blog/",
+			ID:             "1",
+			Added:          added,
+			Description:    "news",
+			ReadingList:    true,
+			Unread:         true,
+		},
+		{
+			BookmarkerName: Safari,
+			Folder:         ReadingListFolder,
+			Title:          "Documentation",
+			Domain:         "go.dev",
+			URI:            "https://go.dev/doc/",
+			ID:             "2",
+			Index:          1,
+			Added:          added,
+			ReadingList:    true,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+}
+
 func setupSafari(t *testing.T) {
 	t.Helper()
 	if err := createTestSafariPlistFile(); err != nil {
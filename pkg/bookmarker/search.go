@@ -0,0 +1,110 @@
+package bookmarker
+
+import (
+	"sort"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// maxUnmatchedLeadingCharPenalty is the minimum score of unmatched leading characters in sahilm/fuzzy
+const maxUnmatchedLeadingCharPenalty = -15
+
+// SearchField is a field of bookmarks which a query is matched with
+type SearchField string
+
+// search fields
+const (
+	SearchFieldTitle  SearchField = "title"
+	SearchFieldDomain SearchField = "domain"
+	SearchFieldURL    SearchField = "url"
+	SearchFieldFolder SearchField = "folder"
+	// SearchFieldKeyword is matched exactly with the whole query rather than fuzzily
+	SearchFieldKeyword SearchField = "keyword"
+)
+
+// SearchWeights are weights of scores of fields. A field whose weight is zero is not searched
+type SearchWeights struct {
+	Title  float64 `mapstructure:"title"`
+	Domain float64 `mapstructure:"domain"`
+	URL    float64 `mapstructure:"url"`
+	Folder float64 `mapstructure:"folder"`
+	// Visits boosts scores of frequently visited bookmarks. zero disables it
+	Visits float64 `mapstructure:"visits"`
+}
+
+// NewSearchWeights returns weights which prefer titles to the other fields
+func NewSearchWeights() *SearchWeights {
+	return &SearchWeights{
+		Title:  1.0,
+		Domain: 0.8,
+		URL:    0.5,
+		Folder: 0.6,
+		Visits: 0.2,
+	}
+}
+
+// SearchResult is a bookmark which matches a query
+type SearchResult struct {
+	Bookmark *Bookmark
+	// Score is the best weighted score of the fields
+	Score float64
+	// Field is the field which has the best score. It tells why the bookmark matches
+	Field SearchField
+}
+
+// Search fuzzy-matches the query with titles, domains, urls and folders of the bookmarks.
+// Results are sorted by score and bookmarks keep their order if the scores are the same
+func (b Bookmarks) Search(query string, w *SearchWeights) []*SearchResult {
+	if w == nil {
+		w = NewSearchWeights()
+	}
+
+	results := make([]*SearchResult, 0, len(b))
+	for _, e := range b {
+		var best *SearchResult
+		fields := []struct {
+			name   SearchField
+			value  string
+			weight float64
+		}{
+			{SearchFieldTitle, e.Title, w.Title},
+			{SearchFieldDomain, e.Domain, w.Domain},
+			{SearchFieldURL, e.URI, w.URL},
+			{SearchFieldFolder, e.Folder, w.Folder},
+		}
+		for _, f := range fields {
+			if f.weight <= 0 || f.value == "" {
+				continue
+			}
+			score, ok := fuzzyScore(query, f.value)
+			if !ok {
+				continue
+			}
+			if s := score * f.weight; best == nil || s > best.Score {
+				best = &SearchResult{Bookmark: e, Score: s, Field: f.name}
+			}
+		}
+		if best != nil {
+			best.Score *= visitBoost(e.VisitCount, w.Visits)
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// fuzzyScore returns a positive score if the pattern matches the value.
+// Note: the penalty of unmatched characters is excluded not to prefer short fields like domains to urls
+func fuzzyScore(pattern, value string) (float64, bool) {
+	matches := fuzzy.Find(pattern, []string{value})
+	if len(matches) == 0 {
+		return 0, false
+	}
+
+	m := matches[0]
+	score := m.Score + len(value) - len(m.MatchedIndexes) - maxUnmatchedLeadingCharPenalty + 1
+	return float64(score), true
+}
@@ -0,0 +1,72 @@
+package bookmarker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBookmarks_Search(t *testing.T) {
+	b := Bookmarks{
+		{Title: "Dashboard", Domain: "grafana.example.com", URI: "https://grafana.example.com/d/abc/overview", Folder: "/Bookmarks Bar/monitoring"},
+		{Title: "Grafana Docs", Domain: "grafana.com", URI: "https://grafana.com/docs/", Folder: "/Bookmarks Bar/docs"},
+		{Title: "Runbook", Domain: "wiki.example.com", URI: "https://wiki.example.com/runbook", Folder: "/Bookmarks Bar/work/infra"},
+	}
+	tests := []struct {
+		name    string
+		query   string
+		weights *SearchWeights
+		want    []*Bookmark
+		fields  []SearchField
+	}{
+		{
+			name:   "a title is preferred to a domain",
+			query:  "grafana",
+			want:   []*Bookmark{b[1], b[0]},
+			fields: []SearchField{SearchFieldTitle, SearchFieldDomain},
+		},
+		{
+			name:   "part of url path",
+			query:  "grafana/d/abc",
+			want:   []*Bookmark{b[0]},
+			fields: []SearchField{SearchFieldURL},
+		},
+		{
+			name:   "folder name",
+			query:  "infra",
+			want:   []*Bookmark{b[2]},
+			fields: []SearchField{SearchFieldFolder},
+		},
+		{
+			name:    "a field whose weight is zero is not searched",
+			query:   "infra",
+			weights: &SearchWeights{Title: 1, Domain: 1, URL: 1},
+			want:    []*Bookmark{},
+			fields:  []SearchField{},
+		},
+		{
+			name:    "weights change the matched field",
+			query:   "grafana",
+			weights: &SearchWeights{Title: 0.1, Domain: 1},
+			want:    []*Bookmark{b[0], b[1]},
+			fields:  []SearchField{SearchFieldDomain, SearchFieldDomain},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := b.Search(tt.query, tt.weights)
+			got := make([]*Bookmark, 0, len(results))
+			fields := make([]SearchField, 0, len(results))
+			for _, r := range results {
+				got = append(got, r.Bookmark)
+				fields = append(fields, r.Field)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("-want +got\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.fields, fields); diff != "" {
+				t.Errorf("-want +got\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package bookmarker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ProfileConfig is a configuration of a browser which has profiles
+type ProfileConfig struct {
+	Enable       bool     `mapstructure:"enable"`
+	ProfileName  string   `mapstructure:"profile_name,omitempty"`
+	ProfileNames []string `mapstructure:"profile_names,omitempty"`
+	AllProfiles  bool     `mapstructure:"all_profiles,omitempty"`
+	ProfilePath  string   `mapstructure:"profile_path,omitempty"`
+	// History searches visited urls of the profiles in addition to bookmarks
+	History bool `mapstructure:"history,omitempty"`
+}
+
+// Enabled returns true if the browser is enabled
+func (c *ProfileConfig) Enabled() bool {
+	return c.Enable
+}
+
+// Profiles returns profile names to search. nil means all profiles.
+// profile_names has priority over profile_name
+func (c *ProfileConfig) Profiles() []string {
+	if c.AllProfiles {
+		return nil
+	}
+	if len(c.ProfileNames) > 0 {
+		return c.ProfileNames
+	}
+	return []string{c.ProfileName}
+}
+
+// SafariConfig is a configuration of safari
+type SafariConfig struct {
+	Enable bool `mapstructure:"enable"`
+	// History searches visited urls in addition to bookmarks
+	History bool `mapstructure:"history,omitempty"`
+}
+
+// Enabled returns true if safari is enabled
+func (c *SafariConfig) Enabled() bool {
+	return c.Enable
+}
+
+// HTMLConfig is a configuration of bookmark html files
+type HTMLConfig struct {
+	Enable bool     `mapstructure:"enable"`
+	Files  []string `mapstructure:"files"`
+}
+
+// Enabled returns true if html files are enabled
+func (c *HTMLConfig) Enabled() bool {
+	return c.Enable
+}
+
+// profileSource is a source of a browser which has profiles
+type profileSource struct {
+	name        bookmarkerName
+	displayName string
+	badge       string
+	// icon is an image file name. the name of the source is used if empty
+	icon               string
+	app                string
+	defaultProfileName string
+	defaultProfilePath string
+	option             func(profilePath string, profileNames ...string) Option
+	history            func(profilePath string, profileNames ...string) Option
+	defaultProfile     func(profilePath string) (*Profile, error)
+	// optIn is true if the source is not detected and available only if it is enabled in the configuration
+	optIn bool
+	// writable is true if bookmarks can be added into the bookmark file like chrome and chromium-based browsers
+	writable bool
+}
+
+func init() {
+	profileSources := []*profileSource{
+		{
+			name: Firefox, displayName: "Firefox", badge: "FF", app: "Firefox",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Firefox/Profiles"),
+			option:             WithFirefox,
+			history:            WithFirefoxHistory,
+			defaultProfile:     DefaultFirefoxProfile,
+		},
+		{
+			// open tabs are opened with firefox, so they share the icon
+			name: FirefoxTabs, displayName: "Firefox Tabs", badge: "TAB", app: "Firefox",
+			icon:               "firefox.png",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Firefox/Profiles"),
+			option:             WithFirefoxTabs,
+			defaultProfile:     DefaultFirefoxProfile,
+			// Note: open tabs are not bookmarks, so they are searched only if configured
+			optIn: true,
+		},
+		{
+			name: Chrome, displayName: "Google Chrome", badge: "CH", app: "Google Chrome",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome"),
+			option:             WithChrome,
+			history:            chromiumHistory(Chrome),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+		{
+			// the reading list is opened with chrome, so they share the icon
+			name: ChromeReadingList, displayName: "Chrome Reading List", badge: "RL", app: "Google Chrome",
+			icon:               "chrome.png",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome"),
+			option:             WithChromeReadingList,
+			defaultProfile:     DefaultChromiumProfile,
+			// Note: items of the reading list are not bookmarks, so they are searched only if configured
+			optIn: true,
+		},
+		{
+			name: ChromeSearchEngines, displayName: "Chrome Search Engines", badge: "SE", app: "Google Chrome",
+			icon:               "chrome.png",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Google/Chrome"),
+			option:             WithChromeSearchEngines,
+			defaultProfile:     DefaultChromiumProfile,
+			// Note: search engines are not bookmarks, so they are searched only if configured
+			optIn: true,
+		},
+		{
+			name: Brave, displayName: "Brave", badge: "BR", app: "Brave Browser",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/BraveSoftware/Brave-Browser"),
+			option:             WithBrave,
+			history:            chromiumHistory(Brave),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+		{
+			name: Edge, displayName: "Microsoft Edge", badge: "ED", app: "Microsoft Edge",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Microsoft Edge"),
+			option:             WithEdge,
+			history:            chromiumHistory(Edge),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+		{
+			name: Vivaldi, displayName: "Vivaldi", badge: "VI", app: "Vivaldi",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Vivaldi"),
+			option:             WithVivaldi,
+			history:            chromiumHistory(Vivaldi),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+		{
+			name: Arc, displayName: "Arc", badge: "AR", app: "Arc",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Arc/User Data"),
+			option:             WithArc,
+			history:            chromiumHistory(Arc),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+		{
+			// Opera stores bookmarks directly under the profile path
+			name: Opera, displayName: "Opera", badge: "OP", app: "Opera",
+			defaultProfileName: "",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/com.operasoftware.Opera"),
+			option:             WithOpera,
+			history:            chromiumHistory(Opera),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+		{
+			name: Chromium, displayName: "Chromium", badge: "CR", app: "Chromium",
+			defaultProfileName: "default",
+			defaultProfilePath: os.ExpandEnv("${HOME}/Library/Application Support/Chromium"),
+			option:             WithChromium,
+			history:            chromiumHistory(Chromium),
+			defaultProfile:     DefaultChromiumProfile,
+			writable:           true,
+		},
+	}
+	for _, p := range profileSources {
+		Register(p.source())
+	}
+
+	Register(&Source{
+		Name:        string(Safari),
+		DisplayName: "Safari",
+		Icon:        "safari.png",
+		Badge:       "SF",
+		App:         "Safari",
+		Decode: func(decode func(v interface{}) error) (SourceConfig, error) {
+			c := new(SafariConfig)
+			if err := decode(c); err != nil {
+				return nil, err
+			}
+			return c, nil
+		},
+		Detect: func() (SourceConfig, error) {
+			if _, err := GetSafariBookmarkFile(); err != nil {
+				return nil, err
+			}
+			return &SafariConfig{Enable: true}, nil
+		},
+		New: func(cfg SourceConfig) Option {
+			if cfg.(*SafariConfig).History {
+				return withOptions(WithSafari(), WithSafariHistory())
+			}
+			return WithSafari()
+		},
+	})
+
+	Register(&Source{
+		Name:        string(HTML),
+		DisplayName: "HTML Bookmarks",
+		Icon:        "html.png",
+		Badge:       "HTML",
+		Decode: func(decode func(v interface{}) error) (SourceConfig, error) {
+			c := new(HTMLConfig)
+			if err := decode(c); err != nil {
+				return nil, err
+			}
+			for i, f := range c.Files {
+				c.Files[i] = os.ExpandEnv(f)
+			}
+			return c, nil
+		},
+		Detect: func() (SourceConfig, error) {
+			// Note: html files are exported to anywhere, so they are available only if configured
+			return nil, errors.New("html error: no bookmark files are configured")
+		},
+		New: func(cfg SourceConfig) Option {
+			return WithHTML(cfg.(*HTMLConfig).Files...)
+		},
+	})
+}
+
+func (p *profileSource) source() *Source {
+	icon := p.icon
+	if icon == "" {
+		icon = string(p.name) + ".png"
+	}
+	src := &Source{
+		Name:        string(p.name),
+		DisplayName: p.displayName,
+		Icon:        icon,
+		Badge:       p.badge,
+		App:         p.app,
+		Decode:      p.decode,
+		Detect:      p.detect,
+		New:         p.new,
+	}
+	if p.writable {
+		src.Add = p.add
+	}
+	return src
+}
+
+// decode returns a configuration of the file.
+// The profile which the browser uses by default is searched if profile_name is omitted
+func (p *profileSource) decode(decode func(v interface{}) error) (SourceConfig, error) {
+	c := &ProfileConfig{
+		ProfilePath: p.defaultProfilePath,
+	}
+	if err := decode(c); err != nil {
+		return nil, err
+	}
+	c.ProfilePath = os.ExpandEnv(c.ProfilePath)
+	if c.ProfileName == "" {
+		c.ProfileName = p.defaultProfileDirName(c.ProfilePath)
+	}
+	return c, nil
+}
+
+// defaultProfileDirName returns a directory name of the profile which the browser uses by default.
+// The default profile name is returned if the browser has no information of profiles
+func (p *profileSource) defaultProfileDirName(profilePath string) string {
+	if p.defaultProfileName == "" {
+		return ""
+	}
+	if profile, err := p.defaultProfile(profilePath); err == nil {
+		return profile.DirName
+	}
+	return p.defaultProfileName
+}
+
+// detect returns a configuration of the profile which the browser uses by default
+func (p *profileSource) detect() (SourceConfig, error) {
+	if p.optIn {
+		return nil, fmt.Errorf("%s error: available only if it is enabled in the configuration", p.name)
+	}
+	profileName := p.defaultProfileDirName(p.defaultProfilePath)
+	m, err := New(p.option(p.defaultProfilePath, profileName))
+	if err != nil {
+		return nil, err
+	}
+	if errs := m.(*Manager).errs; len(errs) > 0 {
+		return nil, errs[0].Err
+	}
+
+	return &ProfileConfig{
+		Enable:      true,
+		ProfileName: profileName,
+		ProfilePath: p.defaultProfilePath,
+	}, nil
+}
+
+func (p *profileSource) new(cfg SourceConfig) Option {
+	c := cfg.(*ProfileConfig)
+	opt := p.option(c.ProfilePath, c.Profiles()...)
+	if !c.History || p.history == nil {
+		return opt
+	}
+	return withOptions(opt, p.history(c.ProfilePath, c.Profiles()...))
+}
+
+// add adds a bookmark into the bookmark file of the profile of the chromium-based browser
+func (p *profileSource) add(cfg SourceConfig, profile, folder, title, uri string) (*Bookmark, error) {
+	c := cfg.(*ProfileConfig)
+	path, err := GetChromiumBookmarkFile(p.name, c.ProfilePath, profile)
+	if err != nil {
+		return nil, err
+	}
+	return AddChromiumBookmark(p.name, path, folder, title, uri)
+}
+
+// chromiumHistory returns a history option of the chromium-based browser
+func chromiumHistory(name bookmarkerName) func(profilePath string, profileNames ...string) Option {
+	return func(profilePath string, profileNames ...string) Option {
+		return WithChromiumHistory(string(name), profilePath, profileNames...)
+	}
+}
+
+// withOptions applies the options in order
+func withOptions(opts ...Option) Option {
+	return func(m *Manager) error {
+		for _, opt := range opts {
+			if err := opt(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package bookmarker
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	// pure go sqlite driver to build without cgo
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSuffixes are files which sqlite creates next to a database file
+var sqliteSuffixes = []string{"-wal", "-journal"}
+
+// openSQLiteSnapshot copies a database file into a temporary directory and opens the copy.
+// Browsers lock their databases while running, so we never read the original files directly.
+// The returned close function closes the database and removes the copy
+func openSQLiteSnapshot(path string) (db *sql.DB, closeFn func(), err error) {
+	dir, err := os.MkdirTemp("", "alfred-bookmarks-")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	snapshot := filepath.Join(dir, filepath.Base(path))
+	if err := copyFile(path, snapshot); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	for _, suffix := range sqliteSuffixes {
+		if _, err := os.Stat(path + suffix); err != nil {
+			continue
+		}
+		if err := copyFile(path+suffix, snapshot+suffix); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+
+	db, err = sql.Open("sqlite", snapshot)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+
+	closeFn = func() {
+		db.Close()
+		cleanup()
+	}
+	return db, closeFn, nil
+}
+
+func copyFile(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
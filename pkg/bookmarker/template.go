@@ -0,0 +1,49 @@
+package bookmarker
+
+import (
+	"net/url"
+	"strings"
+)
+
+// templatePlaceholders are placeholders of url templates like keyword bookmarks of firefox.
+// %s is replaced with the encoded argument and %S is replaced with the argument as it is
+var templatePlaceholders = []string{"%s", "%S"}
+
+// IsTemplate returns true if the url of the bookmark is a template which takes an argument
+func (b *Bookmark) IsTemplate() bool {
+	return isTemplate(b.URI)
+}
+
+// SelectedBy returns true if the bookmark is a url template which the word selects.
+// The word selects a template by the keyword or a prefix of a word of the title regardless of case
+func (b *Bookmark) SelectedBy(word string) bool {
+	if word == "" || !b.IsTemplate() {
+		return false
+	}
+	if strings.EqualFold(b.Keyword, word) {
+		return true
+	}
+	word = strings.ToLower(word)
+	for _, w := range strings.Fields(strings.ToLower(b.Title)) {
+		if strings.HasPrefix(w, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand returns the url whose placeholders are replaced with the argument
+func (b *Bookmark) Expand(arg string) string {
+	// Note: spaces are encoded as %20 like encodeURIComponent of firefox
+	escaped := strings.ReplaceAll(url.QueryEscape(arg), "+", "%20")
+	return strings.NewReplacer("%s", escaped, "%S", arg).Replace(b.URI)
+}
+
+func isTemplate(uri string) bool {
+	for _, p := range templatePlaceholders {
+		if strings.Contains(uri, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package bookmarker
+
+import (
+	"testing"
+)
+
+func TestBookmark_Expand(t *testing.T) {
+	tests := []struct {
+		uri      string
+		arg      string
+		template bool
+		want     string
+	}{
+		{uri: "https://jira.example.com/browse/%s", arg: "PROJ-123", template: true, want: "https://jira.example.com/browse/PROJ-123"},
+		{uri: "https://www.google.com/search?q=%s", arg: "go & rust", template: true, want: "https://www.google.com/search?q=go%20%26%20rust"},
+		{uri: "https://example.com/%S", arg: "a/b", template: true, want: "https://example.com/a/b"},
+		{uri: "https://github.com/", arg: "x", want: "https://github.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			b := &Bookmark{URI: tt.uri}
+			if b.IsTemplate() != tt.template {
+				t.Errorf("want template %v", tt.template)
+			}
+			if got := b.Expand(tt.arg); got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBookmark_SelectedBy(t *testing.T) {
+	b := &Bookmark{Title: "Jira Issues", URI: "https://jira.example.com/browse/%s", Keyword: "j"}
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{word: "J", want: true},
+		{word: "jira", want: true},
+		{word: "iss", want: true},
+		{word: "ira", want: false},
+		{word: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := b.SelectedBy(tt.word); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+	if (&Bookmark{Title: "Jira", URI: "https://jira.example.com/"}).SelectedBy("jira") {
+		t.Errorf("a bookmark which is not a template should not be selected")
+	}
+}
+
+func TestTemplateBookmarks(t *testing.T) {
+	// templates were ignored as their placeholders are invalid escapes
+	root := &firefoxBookmarkEntry{
+		TypeCode: 2,
+		Children: []*firefoxBookmarkEntry{
+			{TypeCode: 1, Title: "Jira", URI: "https://jira.example.com/browse/%s", Keyword: "jira"},
+		},
+	}
+	bookmarks := root.convertToBookmarks("/")
+	if len(bookmarks) != 1 || bookmarks[0].Domain != "jira.example.com" {
+		t.Errorf("unexpected bookmarks %v", bookmarks)
+	}
+}
@@ -0,0 +1,9 @@
+CREATE TABLE meta(key LONGVARCHAR NOT NULL UNIQUE PRIMARY KEY, value LONGVARCHAR);
+INSERT INTO meta VALUES('version','112');
+CREATE TABLE keywords (id INTEGER PRIMARY KEY,short_name VARCHAR NOT NULL,keyword VARCHAR NOT NULL,favicon_url VARCHAR NOT NULL,url VARCHAR NOT NULL,safe_for_autoreplace INTEGER,originating_url VARCHAR,date_created INTEGER DEFAULT 0,usage_count INTEGER DEFAULT 0,input_encodings VARCHAR,suggest_url VARCHAR,prepopulate_id INTEGER DEFAULT 0,created_by_policy INTEGER DEFAULT 0,last_modified INTEGER DEFAULT 0,sync_guid VARCHAR,alternate_urls VARCHAR,image_url VARCHAR,search_url_post_params VARCHAR,suggest_url_post_params VARCHAR,image_url_post_params VARCHAR,new_tab_url VARCHAR,last_visited INTEGER DEFAULT 0, created_from_play_api INTEGER DEFAULT 0, is_active INTEGER DEFAULT 0, starter_pack_id INTEGER DEFAULT 0);
+INSERT INTO keywords VALUES(2,'Google','google.com','https://www.google.com/favicon.ico','{google:baseURL}search?q={searchTerms}&{google:RLZ}{google:originalQueryForSuggestion}{google:assistedQueryStats}{google:searchFieldtrialParameter}{google:iOSSearchLanguage}{google:prefetchSource}{google:searchClient}{google:sourceId}{google:contextualSearchVersion}ie={inputEncoding}',1,'',0,0,'UTF-8','{google:baseSuggestURL}search?{google:searchFieldtrialParameter}client={google:suggestClient}&gs_ri={google:suggestRid}&xssi=t&q={searchTerms}&{google:inputType}{google:omniboxFocusType}{google:cursorPosition}{google:currentPageUrl}{google:pageClassification}{google:clientCacheTimeToLive}{google:searchVersion}{google:sessionToken}{google:prefetchQuery}sugkey={google:suggestAPIKeyParameter}',1,0,13330051200000000,'485bf7d3-0215-45af-87dc-538868000001','[]','{google:baseSearchByImageURL}upload','','','','{google:baseURL}_/chrome/newtab?{google:RLZ}ie={inputEncoding}',0,0,1,0);
+INSERT INTO keywords VALUES(3,'DuckDuckGo','duckduckgo.com','https://duckduckgo.com/favicon.ico','https://duckduckgo.com/?q={searchTerms}',1,'',0,0,'UTF-8','https://duckduckgo.com/ac/?q={searchTerms}&type=list',92,0,13330051200000000,'485bf7d3-0215-45af-87dc-538868000092','[]','','','','','',0,0,1,0);
+INSERT INTO keywords VALUES(4,'GitHub','github.com','https://github.com/favicon.ico','https://github.com/search?q={searchTerms}',1,'https://github.com/',13330051200000000,3,'UTF-8','',0,0,13330051200000000,'0f7a5a3e-8c6f-4f0e-9f3a-7b0f6d2b9c41','[]','','','','','',13330051200000000,0,1,0);
+INSERT INTO keywords VALUES(5,'Jira','jira','','https://jira.example.com/browse/{searchTerms}',0,'',13330051200000000,12,'','',0,0,13330051200000000,'2b3c9c1e-6f3a-4d8b-9a57-1f0e5d7c3a10','[]','','','','','',13330051200000000,0,1,0);
+INSERT INTO keywords VALUES(6,'Example','ex','','https://example.com/search?q={searchTerms}&ie={inputEncoding}',0,'',13330051200000000,0,'','',0,0,13330051200000000,'7d1e2a4b-3c5f-4e6a-8b9c-0d1e2f3a4b5c','[]','','','','','',0,0,1,0);
+INSERT INTO keywords VALUES(7,'Bing','b','https://www.bing.com/sa/simg/favicon-2x.ico','https://www.bing.com/search?q={searchTerms}',0,'',0,5,'UTF-8','',3,0,13330051200000000,'485bf7d3-0215-45af-87dc-538868000003','[]','','','','','',13330051200000000,0,1,0);
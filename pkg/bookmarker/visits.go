@@ -0,0 +1,88 @@
+package bookmarker
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Visit is visit statistics of an url in browser history
+type Visit struct {
+	Count       int
+	LastVisited time.Time
+	// Frecency is a score of frequency and recency which only firefox has
+	Frecency int
+}
+
+// VisitSource is implemented by bookmarkers which can read visit statistics from browser history.
+// Visits returns statistics by url
+type VisitSource interface {
+	Visits() (map[string]*Visit, error)
+}
+
+// visitFileSource is implemented by visit sources which read history files other than the bookmark files.
+// The files are fingerprinted only if visits are attached
+type visitFileSource interface {
+	visitFiles() []string
+}
+
+// WithVisits if called, visit statistics of browser history are attached to bookmarks.
+// Bookmarks of sources which have no history are not changed
+func WithVisits() Option {
+	return func(m *Manager) error {
+		m.visits = true
+		return nil
+	}
+}
+
+// attachVisits sets visit statistics of the bookmarker to the bookmarks.
+// The bookmarks are returned as they are if history can not be read as it is optional
+func attachVisits(bookmarks Bookmarks, b Bookmarker) Bookmarks {
+	vs, ok := b.(VisitSource)
+	if !ok {
+		return bookmarks
+	}
+	visits, err := vs.Visits()
+	if err != nil {
+		return bookmarks
+	}
+
+	for _, bookmark := range bookmarks {
+		if v, ok := visits[bookmark.URI]; ok {
+			bookmark.VisitCount = v.Count
+			bookmark.LastVisited = v.LastVisited
+			bookmark.Frecency = v.Frecency
+		}
+	}
+	return bookmarks
+}
+
+// addVisits adds visit statistics of the duplicate bookmark
+func (b *Bookmark) addVisits(d *Bookmark) {
+	b.VisitCount += d.VisitCount
+	if d.LastVisited.After(b.LastVisited) {
+		b.LastVisited = d.LastVisited
+	}
+	if d.Frecency > b.Frecency {
+		b.Frecency = d.Frecency
+	}
+}
+
+// SortByVisits sorts bookmarks by visit counts and last visited time in descending order.
+// Bookmarks keep their order if they have the same statistics
+func (b Bookmarks) SortByVisits() {
+	sort.SliceStable(b, func(i, j int) bool {
+		if b[i].VisitCount != b[j].VisitCount {
+			return b[i].VisitCount > b[j].VisitCount
+		}
+		return b[i].LastVisited.After(b[j].LastVisited)
+	})
+}
+
+// visitBoost returns a multiplier of a score. It grows logarithmically not to hide better matches
+func visitBoost(count int, weight float64) float64 {
+	if count <= 0 || weight <= 0 {
+		return 1
+	}
+	return 1 + weight*math.Log1p(float64(count))
+}
@@ -0,0 +1,142 @@
+package bookmarker
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManagerBookmarksWithVisits(t *testing.T) {
+	lastVisited := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Chrome saves History next to Bookmarks
+	dir := t.TempDir()
+	bookmarkPath := filepath.Join(dir, "Bookmarks")
+	data, err := os.ReadFile(testChromeBookmarkJSONFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, bookmarkPath, string(data))
+	db := openTestDB(t, filepath.Join(dir, "History"))
+	if _, err := db.Exec(`CREATE TABLE urls (id INTEGER PRIMARY KEY, url LONGVARCHAR, visit_count INTEGER, last_visit_time INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO urls (url, visit_count, last_visit_time) VALUES (?, ?, ?), (?, ?, ?)`,
+		"https://github.com/", 3, lastVisited.UnixMicro()+chromeEpochOffset,
+		"https://example.com/", 10, lastVisited.UnixMicro()+chromeEpochOffset); err != nil {
+		t.Fatal(err)
+	}
+
+	placesPath := createTestFirefoxPlaces(t)
+	db = openTestDB(t, placesPath)
+	if _, err := db.Exec(`UPDATE moz_places SET visit_count = 2, frecency = 150, last_visit_date = ? WHERE url = ?`,
+		lastVisited.Add(time.Hour).UnixMicro(), "https://github.com/"); err != nil {
+		t.Fatal(err)
+	}
+
+	newManager := func(opts ...Option) *Manager {
+		m, err := New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manager := m.(*Manager)
+		manager.add(Chrome, testProfile, NewChrome(bookmarkPath))
+		manager.add(Firefox, testProfile, &firefoxFallbackBookmark{places: NewFirefoxPlaces(placesPath)})
+		return manager
+	}
+	find := func(bookmarks Bookmarks, name bookmarkerName, uri string) *Bookmark {
+		for _, b := range bookmarks {
+			if b.BookmarkerName == name && b.URI == uri {
+				return b
+			}
+		}
+		t.Fatalf("%s is not found in %s", uri, name)
+		return nil
+	}
+
+	bookmarks, err := newManager(WithVisits()).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chrome := find(bookmarks, Chrome, "https://github.com/")
+	if chrome.VisitCount != 3 || !chrome.LastVisited.Equal(lastVisited) {
+		t.Errorf("unexpected chrome visits %d %v", chrome.VisitCount, chrome.LastVisited)
+	}
+	firefox := find(bookmarks, Firefox, "https://github.com/")
+	if firefox.VisitCount != 2 || firefox.Frecency != 150 {
+		t.Errorf("unexpected firefox visits %d %d", firefox.VisitCount, firefox.Frecency)
+	}
+	if b := find(bookmarks, Chrome, "https://www.google.com/"); b.VisitCount != 0 {
+		t.Errorf("a bookmark which is not visited has visits %d", b.VisitCount)
+	}
+
+	// visits of duplicate bookmarks are summed up
+	bookmarks, err = newManager(WithVisits(), WithRemoveDuplicates()).Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := find(bookmarks, Chrome, "https://github.com/")
+	if merged.VisitCount != 5 || !merged.LastVisited.Equal(lastVisited.Add(time.Hour)) || merged.Frecency != 150 {
+		t.Errorf("unexpected merged visits %d %v %d", merged.VisitCount, merged.LastVisited, merged.Frecency)
+	}
+
+	// visits are optional
+	bookmarks, err = newManager().Bookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := find(bookmarks, Chrome, "https://github.com/"); b.VisitCount != 0 {
+		t.Errorf("visits are attached without the option %d", b.VisitCount)
+	}
+}
+
+func TestBookmarks_SortByVisits(t *testing.T) {
+	now := time.Now()
+	b := Bookmarks{
+		{Title: "a"},
+		{Title: "b", VisitCount: 1, LastVisited: now.Add(-time.Hour)},
+		{Title: "c", VisitCount: 3},
+		{Title: "d", VisitCount: 1, LastVisited: now},
+		{Title: "e"},
+	}
+	b.SortByVisits()
+	got := make([]string, 0, len(b))
+	for _, e := range b {
+		got = append(got, e.Title)
+	}
+	if diff := cmp.Diff([]string{"c", "d", "b", "a", "e"}, got); diff != "" {
+		t.Errorf("-want +got\n%s", diff)
+	}
+}
+
+func TestBookmarks_SearchWithVisits(t *testing.T) {
+	b := Bookmarks{
+		{Title: "Grafana", URI: "https://grafana.com/"},
+		{Title: "Grafana", URI: "https://grafana.example.com/", VisitCount: 20},
+	}
+	results := b.Search("grafana", nil)
+	if len(results) != 2 || results[0].Bookmark != b[1] {
+		t.Errorf("a frequently visited bookmark should come first")
+	}
+
+	results = b.Search("grafana", &SearchWeights{Title: 1})
+	if len(results) != 2 || results[0].Bookmark != b[0] {
+		t.Errorf("bookmarks should keep the order if visits are not weighted")
+	}
+}
+
+func openTestDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
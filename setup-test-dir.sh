@@ -4,6 +4,9 @@ PROFILE=default
 FIREFOX_DIR="${HOME}/Library/Application Support/Firefox/Profiles/xxxxx.${PROFILE}/bookmarkbackups"
 CHROME_DIR="${HOME}/Library/Application Support/Google/Chrome/${PROFILE}"
 CHROME_BOOKMARK_FILE="Bookmarks"
+CHROME_SECOND_DIR="${HOME}/Library/Application Support/Google/Chrome/Profile 1"
+BRAVE_DIR="${HOME}/Library/Application Support/BraveSoftware/Brave-Browser/${PROFILE}"
+OPERA_DIR="${HOME}/Library/Application Support/com.operasoftware.Opera"
 SAFARI_DIR="${HOME}/Library/Safari"
 SAFARI_BOOKMARK_FILE="Bookmarks.plist"
 TEST_DIR="$(pwd)/pkg/bookmarker/testdata"
@@ -12,8 +15,14 @@ CHROME_TEST_FILE="${TEST_DIR}/test-chrome-bookmarks.json"
 SAFARI_TEST_FILE="${TEST_DIR}/test-safari-bookmarks.plist"
 mkdir -p "${FIREFOX_DIR}"
 mkdir -p "${CHROME_DIR}"
+mkdir -p "${CHROME_SECOND_DIR}"
+mkdir -p "${BRAVE_DIR}"
+mkdir -p "${OPERA_DIR}"
 mkdir -p "${SAFARI_DIR}"
 
 cp "${FIREFOX_TEST_FILE}" "${FIREFOX_DIR}/"
 cp "${CHROME_TEST_FILE}" "${CHROME_DIR}/${CHROME_BOOKMARK_FILE}"
+cp "${CHROME_TEST_FILE}" "${CHROME_SECOND_DIR}/${CHROME_BOOKMARK_FILE}"
+cp "${CHROME_TEST_FILE}" "${BRAVE_DIR}/${CHROME_BOOKMARK_FILE}"
+cp "${CHROME_TEST_FILE}" "${OPERA_DIR}/${CHROME_BOOKMARK_FILE}"
 cp "${SAFARI_TEST_FILE}" "${SAFARI_DIR}/${SAFARI_BOOKMARK_FILE}"